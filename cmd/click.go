@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var clickCmd = &cobra.Command{
+	Use:   "click <selector>",
+	Short: "Click an element",
+	Long: `Clicks the element matching a CSS selector.
+Accepts "#12"-style references to elements from the last 'brow screenshot --annotate'.
+Selectors may step into iframes (including cross-site ones) and open shadow roots with
+" >>> ", as printed by 'brow pick': 'iframe#login >>> my-form >>> button[type=submit]'.
+Use --frame to resolve the selector inside an iframe (see 'brow frames').`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClick,
+}
+
+func init() {
+	rootCmd.AddCommand(clickCmd)
+	addFrameFlag(clickCmd)
+}
+
+func runClick(_ *cobra.Command, args []string) error {
+	selector, err := resolveSelector(args[0])
+	if err != nil {
+		return err
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if frameSpec != "" {
+		err = browser.Page().ClickInFrame(frameSpec, selector)
+	} else {
+		err = browser.Page().Click(selector)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Clicked: %s\n", selector)
+	return nil
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
-	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +15,9 @@ var (
 	domain       string
 	setCookie    string
 	clearCookies bool
+	deleteCookie string
+	exportFormat string
+	importFormat string
 )
 
 var cookiesCmd = &cobra.Command{
@@ -21,15 +26,54 @@ var cookiesCmd = &cobra.Command{
 	Long: `Manages browser cookies.
 By default, retrieves all cookies as JSON.
 Use --set to set a cookie (format: "name=value; domain=.example.com; path=/")
-Use --clear to clear all cookies.`,
+Cookies are set through the DevTools protocol, so HttpOnly cookies and cookies for other
+domains work too. Supported attributes: Domain, Path, Expires, Max-Age, Secure, HttpOnly,
+SameSite and Partitioned. Without a Domain, the cookie is set for the current page.
+Partitioned cookies are stored under the current page's site, as when a site embeds
+a third-party widget: navigate to the embedding site first.
+Use --delete to delete a cookie by name (all domains, or only --domain).
+Use --clear to clear all cookies.
+
+--domain matches the domain and its subdomains, plus domain cookies (".example.com")
+that would be sent to it. Use 'brow cookies export' and 'brow cookies import' to move
+sessions to and from other tools.`,
 	RunE: runCookies,
 }
 
+var cookiesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export cookies as JSON or a Netscape cookies.txt file",
+	Long: `Writes all cookies (or those matching --domain) to stdout.
+The netscape format is the cookies.txt format understood by curl (-b), wget and yt-dlp.`,
+	Example: `  brow cookies export --format netscape > cookies.txt
+  brow cookies export --domain example.com > cookies.json`,
+	Args: cobra.NoArgs,
+	RunE: runCookiesExport,
+}
+
+var cookiesImportCmd = &cobra.Command{
+	Use:   "import <file | ->",
+	Short: "Import cookies from JSON or a Netscape cookies.txt file",
+	Long: `Sets cookies from a file (or stdin with "-") previously written by 'brow cookies export'
+or another tool. The format is detected automatically unless --format is given.`,
+	Example: `  brow cookies import cookies.txt
+  brow cookies import --domain example.com cookies.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCookiesImport,
+}
+
 func init() {
 	rootCmd.AddCommand(cookiesCmd)
-	cookiesCmd.Flags().StringVarP(&domain, "domain", "d", "", "Filter cookies by domain")
-	cookiesCmd.Flags().StringVarP(&setCookie, "set", "s", "", "Set a cookie (format: name=value)")
+	cookiesCmd.Flags().StringVarP(&domain, "domain", "d", "", "Filter cookies by domain (also limits --delete)")
+	cookiesCmd.Flags().StringVarP(&setCookie, "set", "s", "", "Set a cookie (format: name=value; attribute=...)")
+	cookiesCmd.Flags().StringVar(&deleteCookie, "delete", "", "Delete cookies with this name")
 	cookiesCmd.Flags().BoolVarP(&clearCookies, "clear", "c", false, "Clear all cookies")
+
+	cookiesCmd.AddCommand(cookiesExportCmd, cookiesImportCmd)
+	cookiesExportCmd.Flags().StringVarP(&domain, "domain", "d", "", "Only export cookies for this domain")
+	cookiesExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "Output format: json or netscape")
+	cookiesImportCmd.Flags().StringVarP(&domain, "domain", "d", "", "Only import cookies for this domain")
+	cookiesImportCmd.Flags().StringVarP(&importFormat, "format", "f", "", "Input format: json or netscape (default: detect)")
 }
 
 func runCookies(_ *cobra.Command, _ []string) error {
@@ -43,12 +87,17 @@ func runCookies(_ *cobra.Command, _ []string) error {
 		return setACookie()
 	}
 
+	// Delete cookie
+	if deleteCookie != "" {
+		return deleteACookie()
+	}
+
 	// Get cookies (default)
 	return getCookies()
 }
 
 func getCookies() error {
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -72,7 +121,7 @@ func getCookies() error {
 }
 
 func setACookie() error {
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -88,8 +137,30 @@ func setACookie() error {
 	return nil
 }
 
+func deleteACookie() error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	deleted, err := browser.Page().DeleteCookie(deleteCookie, domain)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("no cookie named %q found", deleteCookie)
+	}
+
+	fmt.Printf("Deleted %d cookie(s) named %s\n", deleted, deleteCookie)
+	return nil
+}
+
 func clearAllCookies() error {
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -104,3 +175,73 @@ func clearAllCookies() error {
 	fmt.Println("All cookies cleared")
 	return nil
 }
+
+func runCookiesExport(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	cookies, err := browser.Page().GetCookies(domain)
+	if err != nil {
+		return err
+	}
+
+	output, err := operations.FormatCookies(cookies, operations.CookieFormat(exportFormat))
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(output)
+	return err
+}
+
+func runCookiesImport(_ *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cookies: %w", err)
+	}
+
+	cookies, err := operations.ParseCookies(data, operations.CookieFormat(importFormat))
+	if err != nil {
+		return err
+	}
+
+	if domain != "" {
+		filtered := cookies[:0]
+		for _, c := range cookies {
+			if operations.CookieMatchesDomain(c.Domain, domain) {
+				filtered = append(filtered, c)
+			}
+		}
+		cookies = filtered
+	}
+
+	if len(cookies) == 0 {
+		return fmt.Errorf("no cookies to import")
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if err := browser.Page().ImportCookies(cookies); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d cookies\n", len(cookies))
+	return nil
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/matejch/brow/pkg/client"
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+// dialogPolicyState is the state file holding the session's dialog policy
+const dialogPolicyState = "dialog-policy"
+
+// dialogLogFile is the state file listing dialogs seen by brow, one JSON object per line
+const dialogLogFile = "dialogs.log"
+
+var (
+	// dialogFlag is the --dialog policy for a single command
+	dialogFlag string
+
+	dialogLogJSON  bool
+	dialogLogClear bool
+)
+
+var dialogCmd = &cobra.Command{
+	Use:   "dialog",
+	Short: "Answer JavaScript dialogs and set how brow answers them",
+	Long: `Handles alert(), confirm(), prompt() and beforeunload dialogs.
+
+While brow is connected, a dialog blocks the page until it is answered, so commands would
+wait for their timeout. A policy answers dialogs automatically as they open, and answers a
+dialog left open from earlier before the command runs:
+
+  accept            Press OK (prompt() gets its default value)
+  accept=<text>     Press OK, answering prompt() with text
+  dismiss           Press Cancel
+  none              Leave dialogs open (default)
+
+Set the policy for every command with 'brow dialog policy', or for one command with the
+global --dialog flag. Dialogs left open can be answered with 'brow dialog accept' or
+'brow dialog dismiss'. Every dialog brow sees is recorded in a log ('brow dialog log').`,
+	Example: `  brow dialog policy dismiss
+  brow --dialog accept click '#delete'
+  brow --dialog 'accept=Jane' click '#rename'
+  brow dialog accept
+  brow dialog log`,
+}
+
+var dialogAcceptCmd = &cobra.Command{
+	Use:   "accept [prompt text]",
+	Short: "Accept the open dialog, answering a prompt with text",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDialogAnswer,
+}
+
+var dialogDismissCmd = &cobra.Command{
+	Use:   "dismiss",
+	Short: "Dismiss the open dialog",
+	Args:  cobra.NoArgs,
+	RunE:  runDialogAnswer,
+}
+
+var dialogPolicyCmd = &cobra.Command{
+	Use:   "policy [accept | accept=<text> | dismiss | none]",
+	Short: "Show or set how dialogs are answered by later commands",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDialogPolicy,
+}
+
+var dialogLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List dialogs seen by brow",
+	Args:  cobra.NoArgs,
+	RunE:  runDialogLog,
+}
+
+func init() {
+	rootCmd.AddCommand(dialogCmd)
+	dialogCmd.AddCommand(dialogAcceptCmd, dialogDismissCmd, dialogPolicyCmd, dialogLogCmd)
+	dialogLogCmd.Flags().BoolVarP(&dialogLogJSON, "json", "j", false, "Output as JSON")
+	dialogLogCmd.Flags().BoolVar(&dialogLogClear, "clear", false, "Clear the log")
+}
+
+// newBrowser connects like client.New and applies the dialog policy: a dialog left open is
+// answered first, and dialogs opened while connected are answered and logged
+func newBrowser(cfg *config.Config) (*client.Browser, error) {
+	browser, err := client.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := resolveDialogPolicy()
+	if err != nil {
+		browser.Close()
+		return nil, err
+	}
+
+	if policy.Action != "" {
+		// Best effort: a tab that doesn't answer fails later with a clearer error
+		dialogs, _ := browser.AnswerPendingDialogs(policy)
+		for _, d := range dialogs {
+			logDialog(d)
+		}
+	}
+
+	browser.HandleDialogs(policy, func(d operations.Dialog) {
+		logDialog(d)
+		if d.Action == "" {
+			fmt.Fprintf(os.Stderr, "The page opened a dialog (%s: %q) and waits for an answer; run 'brow dialog accept' or 'brow dialog dismiss', or set --dialog\n",
+				d.Type, d.Message)
+		}
+	})
+	return browser, nil
+}
+
+// resolveDialogPolicy returns the --dialog policy, or the one set with 'brow dialog policy'
+func resolveDialogPolicy() (operations.DialogPolicy, error) {
+	if dialogFlag != "" {
+		return operations.ParseDialogPolicy(dialogFlag)
+	}
+
+	var saved string
+	if err := loadState(dialogPolicyState, &saved); err != nil {
+		return operations.DialogPolicy{}, err
+	}
+	return operations.ParseDialogPolicy(saved)
+}
+
+func runDialogAnswer(cmd *cobra.Command, args []string) error {
+	policy := operations.DialogPolicy{Action: operations.DialogDismiss}
+	if cmd.Name() == "accept" {
+		policy.Action = operations.DialogAccept
+		if len(args) > 0 {
+			policy.PromptText = args[0]
+		}
+	}
+
+	// Connect without newBrowser: answering the dialog must not depend on the policy
+	browser, err := client.New(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	dialogs, err := browser.AnswerPendingDialogs(policy)
+	for _, d := range dialogs {
+		logDialog(d)
+		verb := "Accepted"
+		if d.Action == operations.DialogDismiss {
+			verb = "Dismissed"
+		}
+		fmt.Printf("%s %s: %s\n", verb, d.Type, d.Message)
+	}
+	if len(dialogs) > 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("no dialog is open")
+}
+
+func runDialogPolicy(_ *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		policy, err := resolveDialogPolicy()
+		if err != nil {
+			return err
+		}
+		fmt.Println(policy)
+		return nil
+	}
+
+	policy, err := operations.ParseDialogPolicy(args[0])
+	if err != nil {
+		return err
+	}
+	if policy.Action == "" {
+		if err := removeState(dialogPolicyState); err != nil {
+			return err
+		}
+	} else if err := saveState(dialogPolicyState, policy.String()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Dialog policy: %s\n", policy)
+	return nil
+}
+
+func runDialogLog(_ *cobra.Command, _ []string) error {
+	path, err := statePath(dialogLogFile)
+	if err != nil {
+		return err
+	}
+
+	if dialogLogClear {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear dialog log: %w", err)
+		}
+		fmt.Println("Dialog log cleared")
+		return nil
+	}
+
+	dialogs, err := readDialogLog(path)
+	if err != nil {
+		return err
+	}
+
+	if dialogLogJSON {
+		output, err := json.MarshalIndent(dialogs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format dialogs: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(dialogs) == 0 {
+		fmt.Println("No dialogs seen")
+		return nil
+	}
+	for _, d := range dialogs {
+		action := string(d.Action)
+		if action == "" {
+			action = "left open"
+		}
+		if d.PromptText != "" {
+			action += fmt.Sprintf(" (%q)", d.PromptText)
+		}
+		fmt.Printf("%s\t%s\t%q\t%s\t%s\n", d.Time.Format("2006-01-02 15:04:05"), d.Type, d.Message, action, d.URL)
+	}
+	return nil
+}
+
+// logDialog appends a dialog to the dialog log
+func logDialog(d operations.Dialog) {
+	path, err := statePath(dialogLogFile)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// readDialogLog reads the dialog log, skipping damaged lines
+func readDialogLog(path string) ([]operations.Dialog, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dialog log: %w", err)
+	}
+	defer f.Close()
+
+	var dialogs []operations.Dialog
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var d operations.Dialog
+		if json.Unmarshal(scanner.Bytes(), &d) == nil {
+			dialogs = append(dialogs, d)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dialog log: %w", err)
+	}
+	return dialogs, nil
+}
@@ -1,25 +1,64 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
 	"github.com/spf13/cobra"
 )
 
 var (
-	rawOutput  bool
-	jsonOutput bool
+	rawOutput   bool
+	jsonOutput  bool
+	evalFile    string
+	evalArgs    []string
+	evalArgJSON string
+	evalAwait   bool
+	evalTimeout time.Duration
+	evalIsolate bool
+	evalRich    bool
 )
 
 var evalCmd = &cobra.Command{
-	Use:   "eval <javascript>",
+	Use:   "eval [javascript | -]",
 	Short: "Execute JavaScript in the current page",
 	Long: `Executes JavaScript code in the current page context and returns the result.
-Results are automatically formatted as JSON unless --raw is specified.`,
-	Args: cobra.ExactArgs(1),
+Results are automatically formatted as JSON unless --raw is specified.
+Use --frame to run the script inside an iframe, including cross-origin ones (see 'brow frames').
+
+The script is the argument, standard input when the argument is "-", or a file with --file.
+The value of the last statement is returned, as in the DevTools console.
+
+Data is passed in with --arg name=value (a string) and --args-json '{"name": <JSON>}'
+(typed values); each becomes a constant with that name in the script. Values are sent as
+JSON through the protocol, never pasted into the source, so no quoting or escaping is needed.
+
+With --await, a returned promise is awaited and its value printed. --timeout bounds the
+whole evaluation, including synchronous loops in the page.
+
+With --isolated, the script runs in brow's isolated world: it shares the DOM, cookies and
+storage with the page but not its JavaScript globals, so sites that override JSON,
+Array.from or fetch can't break extraction scripts. Page variables are not visible there.
+
+With --rich, values JSON can't hold are kept instead of printed as null or {}: DOM nodes show
+their selector and a summary of their HTML, and Map, Set, BigInt, undefined, NaN, dates,
+errors and circular references are printed as objects tagged with a "$brow" type.`,
+	Example: `  brow eval 'document.title'
+  brow eval -f scrape.js
+  cat scrape.js | brow eval -
+  brow eval --arg q="it's \"quoted\"" 'document.querySelector("input").value = q'
+  brow eval --args-json '{"limit": 10, "tags": ["a", "b"]}' 'tags.slice(0, limit)'
+  brow eval --await 'fetch("/api/me").then(r => r.json())'
+  brow eval --isolated 'Array.from(document.links, a => a.href)'
+  brow eval --rich 'document.querySelectorAll("nav a")'`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runEval,
 }
 
@@ -27,12 +66,28 @@ func init() {
 	rootCmd.AddCommand(evalCmd)
 	evalCmd.Flags().BoolVarP(&rawOutput, "raw", "r", false, "Output raw result without formatting")
 	evalCmd.Flags().BoolVarP(&jsonOutput, "json", "j", true, "Format output as JSON (default true)")
+	evalCmd.Flags().StringVarP(&evalFile, "file", "f", "", "Read the script from a file (- for stdin)")
+	evalCmd.Flags().StringArrayVar(&evalArgs, "arg", nil, "Pass a string constant as name=value (repeatable)")
+	evalCmd.Flags().StringVar(&evalArgJSON, "args-json", "", "Pass typed constants as a JSON object")
+	evalCmd.Flags().BoolVarP(&evalAwait, "await", "a", false, "Await a returned promise and print its value")
+	evalCmd.Flags().DurationVarP(&evalTimeout, "timeout", "t", 30*time.Second, "Maximum evaluation time (0 for no limit)")
+	evalCmd.Flags().BoolVarP(&evalIsolate, "isolated", "i", false, "Run in an isolated world, away from the page's globals")
+	evalCmd.Flags().BoolVar(&evalRich, "rich", false, "Keep nodes, Map, Set, BigInt, undefined, NaN and cycles in the result")
+	addFrameFlag(evalCmd)
 }
 
 func runEval(_ *cobra.Command, args []string) error {
-	script := args[0]
+	script, err := readScript(args)
+	if err != nil {
+		return err
+	}
 
-	browser, err := client.New(&config.Config{
+	scriptArgs, err := parseEvalArgs()
+	if err != nil {
+		return err
+	}
+
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -40,7 +95,14 @@ func runEval(_ *cobra.Command, args []string) error {
 	}
 	defer browser.Close()
 
-	result, err := browser.Page().Eval(script)
+	result, err := browser.Page().EvalWithOptions(script, operations.EvalOptions{
+		Args:         scriptArgs,
+		AwaitPromise: evalAwait,
+		Timeout:      evalTimeout,
+		Frame:        frameSpec,
+		Isolated:     evalIsolate,
+		Rich:         evalRich,
+	})
 	if err != nil {
 		return err
 	}
@@ -60,3 +122,63 @@ func runEval(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// readScript returns the script from --file, stdin ("-") or the argument
+func readScript(args []string) (string, error) {
+	source := evalFile
+	if source == "" {
+		if len(args) == 0 {
+			return "", fmt.Errorf("provide a script, - to read it from stdin, or --file")
+		}
+		if args[0] != "-" {
+			return args[0], nil
+		}
+		source = "-"
+	} else if len(args) > 0 {
+		return "", fmt.Errorf("--file cannot be combined with a script argument")
+	}
+
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read script: %w", err)
+	}
+	return string(data), nil
+}
+
+// parseEvalArgs merges --args-json and --arg into the script's constants; --arg wins
+func parseEvalArgs() (map[string]interface{}, error) {
+	scriptArgs := map[string]interface{}{}
+
+	if evalArgJSON != "" {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(evalArgJSON)))
+		decoder.UseNumber()
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("--args-json must be a JSON object: %w", err)
+		}
+		object, ok := decoded.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("--args-json must be a JSON object")
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			return nil, fmt.Errorf("--args-json must be a single JSON object")
+		}
+		scriptArgs = object
+	}
+
+	for _, arg := range evalArgs {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --arg %q: expected name=value", arg)
+		}
+		scriptArgs[name] = value
+	}
+
+	return scriptArgs, nil
+}
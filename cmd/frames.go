@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// frameSpec is the --frame flag shared by commands that can target an iframe
+var frameSpec string
+
+var framesJSON bool
+
+var framesCmd = &cobra.Command{
+	Use:   "frames",
+	Short: "List the frames of the current page",
+	Long: `Lists the frame tree of the current page with each frame's name, URL and the
+selector of its iframe element. Cross-site iframes rendered in their own process (OOPIFs)
+are included and marked.
+
+Commands with a --frame flag (eval, click, type, screenshot) accept a frame name, a
+substring of the frame's URL or a CSS selector of the iframe element, tried in that order.`,
+	Example: `  brow frames
+  brow frames --json | jq -r '.[].url'
+  brow eval --frame checkout 'document.title'
+  brow click --frame js.stripe.com 'button[type=submit]'
+  brow screenshot --frame 'iframe#preview' preview.png`,
+	Args: cobra.NoArgs,
+	RunE: runFrames,
+}
+
+func init() {
+	rootCmd.AddCommand(framesCmd)
+	framesCmd.Flags().BoolVarP(&framesJSON, "json", "j", false, "Print the frames as JSON")
+}
+
+// addFrameFlag registers --frame on a command
+func addFrameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&frameSpec, "frame", "", "Run in an iframe: frame name, URL substring or iframe CSS selector")
+}
+
+func runFrames(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	frames, err := browser.Page().Frames()
+	if err != nil {
+		return err
+	}
+
+	if framesJSON {
+		output, err := json.MarshalIndent(frames, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format frames: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	for _, frame := range frames {
+		line := strings.Repeat("  ", frame.Depth) + frame.URL
+		if frame.Name != "" {
+			line += fmt.Sprintf(" name=%q", frame.Name)
+		}
+		if frame.Selector != "" {
+			line += fmt.Sprintf(" selector=%q", frame.Selector)
+		}
+		if frame.OutOfProcess {
+			line += " (out-of-process)"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	idbOrigin string
+	idbLimit  int
+)
+
+var idbCmd = &cobra.Command{
+	Use:   "idb",
+	Short: "Inspect, export and clear IndexedDB",
+	Long: `Works with IndexedDB through the DevTools protocol.
+By default the current page's origin is used; --origin selects another origin that is
+loaded in the current tab (for example in an iframe).`,
+}
+
+var idbListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List databases and object stores with record counts",
+	Args:  cobra.NoArgs,
+	RunE:  runIDBList,
+}
+
+var idbDumpCmd = &cobra.Command{
+	Use:   "dump <db> <store>",
+	Short: "Print the records of an object store as NDJSON",
+	Long: `Prints one JSON object per line with the record's key and value, in key order.
+Values are converted with JSON.stringify, so Map, Set and Blob values come out as {}.`,
+	Example: `  brow idb dump app-db settings
+  brow idb dump app-db messages --limit 50 | jq .value.text`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIDBDump,
+}
+
+var idbClearCmd = &cobra.Command{
+	Use:   "clear [db [store]]",
+	Short: "Clear an object store, delete a database, or delete all databases",
+	Example: `  brow idb clear app-db messages   # Empty one object store
+  brow idb clear app-db            # Delete the database
+  brow idb clear                   # Delete every database of the origin`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runIDBClear,
+}
+
+func init() {
+	rootCmd.AddCommand(idbCmd)
+	idbCmd.AddCommand(idbListCmd, idbDumpCmd, idbClearCmd)
+	idbCmd.PersistentFlags().StringVarP(&idbOrigin, "origin", "o", "", "Origin to use (default: current page)")
+	idbDumpCmd.Flags().IntVarP(&idbLimit, "limit", "n", 0, "Maximum number of records (0 for all)")
+}
+
+func runIDBList(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	databases, err := browser.Page().ListIndexedDB(idbOrigin)
+	if err != nil {
+		return err
+	}
+
+	output, err := json.MarshalIndent(databases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format databases: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
+func runIDBDump(_ *cobra.Command, args []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	return browser.Page().DumpIndexedDB(idbOrigin, args[0], args[1], idbLimit, func(record operations.IDBRecord) error {
+		return encoder.Encode(record)
+	})
+}
+
+func runIDBClear(_ *cobra.Command, args []string) error {
+	var database, store string
+	if len(args) > 0 {
+		database = args[0]
+	}
+	if len(args) > 1 {
+		store = args[1]
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if err := browser.Page().ClearIndexedDB(idbOrigin, database, store); err != nil {
+		return err
+	}
+
+	switch {
+	case store != "":
+		fmt.Printf("Cleared object store: %s/%s\n", database, store)
+	case database != "":
+		fmt.Printf("Deleted database: %s\n", database)
+	default:
+		fmt.Println("Deleted all IndexedDB databases")
+	}
+	return nil
+}
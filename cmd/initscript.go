@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matejch/brow/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// initScriptsState is the state file holding scripts registered with 'brow init-script add'
+const initScriptsState = "init-scripts"
+
+// initScript is a script run in every new document by commands that navigate
+type initScript struct {
+	ID     int       `json:"id"`
+	Name   string    `json:"name"`
+	Source string    `json:"source"`
+	Added  time.Time `json:"added"`
+}
+
+var (
+	initScriptName string
+	initScriptJSON bool
+	initScriptAll  bool
+)
+
+var initScriptCmd = &cobra.Command{
+	Use:   "init-script",
+	Short: "Manage scripts that run before page scripts on every new document",
+	Long: `Registers JavaScript that runs at the start of every new document, in the main frame and
+same-site iframes, before any of the page's own scripts. Cross-site iframes rendered in
+their own process (see 'brow frames') don't run it. Use it to stub Date.now or Math.random,
+disable animations, or install test hooks that must exist before the page loads.
+
+Scripts are saved per debugging port and applied by 'brow nav' and 'brow repl'. Chrome
+forgets them when a command's connection closes, so pages loaded by other means, such as
+a click that navigates, don't run them.`,
+}
+
+var initScriptAddCmd = &cobra.Command{
+	Use:   "add <file.js | ->",
+	Short: "Add a script from a file or stdin",
+	Example: `  brow init-script add freeze-time.js
+  echo 'Date.now = () => 1700000000000' | brow init-script add - --name freeze-time
+  brow nav https://example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInitScriptAdd,
+}
+
+var initScriptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered scripts",
+	Args:  cobra.NoArgs,
+	RunE:  runInitScriptList,
+}
+
+var initScriptRemoveCmd = &cobra.Command{
+	Use:     "remove [id | name]...",
+	Aliases: []string{"rm"},
+	Short:   "Remove scripts by id or name",
+	Example: `  brow init-script remove 2
+  brow init-script remove freeze-time
+  brow init-script remove --all`,
+	RunE: runInitScriptRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(initScriptCmd)
+	initScriptCmd.AddCommand(initScriptAddCmd, initScriptListCmd, initScriptRemoveCmd)
+	initScriptAddCmd.Flags().StringVarP(&initScriptName, "name", "n", "", "Name of the script (default: file name)")
+	initScriptListCmd.Flags().BoolVarP(&initScriptJSON, "json", "j", false, "Output as JSON, including sources")
+	initScriptRemoveCmd.Flags().BoolVar(&initScriptAll, "all", false, "Remove all scripts")
+}
+
+func runInitScriptAdd(_ *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read script: %w", err)
+	}
+
+	name := initScriptName
+	if name == "" {
+		name = "stdin"
+		if args[0] != "-" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+	}
+
+	scripts, err := loadInitScripts()
+	if err != nil {
+		return err
+	}
+
+	id := 1
+	for _, s := range scripts {
+		if s.ID >= id {
+			id = s.ID + 1
+		}
+	}
+	scripts = append(scripts, initScript{ID: id, Name: name, Source: string(data), Added: time.Now()})
+
+	if err := saveState(initScriptsState, scripts); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added init script %d (%s); it runs on documents loaded by the next 'brow nav'\n", id, name)
+	return nil
+}
+
+func runInitScriptList(_ *cobra.Command, _ []string) error {
+	scripts, err := loadInitScripts()
+	if err != nil {
+		return err
+	}
+
+	if initScriptJSON {
+		output, err := json.MarshalIndent(scripts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format init scripts: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(scripts) == 0 {
+		fmt.Println("No init scripts")
+		return nil
+	}
+	for _, s := range scripts {
+		preview, _, _ := strings.Cut(strings.TrimSpace(s.Source), "\n")
+		if len(preview) > 60 {
+			preview = preview[:57] + "..."
+		}
+		fmt.Printf("%d\t%s\t%d bytes\t%s\n", s.ID, s.Name, len(s.Source), preview)
+	}
+	return nil
+}
+
+func runInitScriptRemove(_ *cobra.Command, args []string) error {
+	if initScriptAll {
+		if len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with ids or names")
+		}
+		if err := removeState(initScriptsState); err != nil {
+			return err
+		}
+		fmt.Println("Removed all init scripts")
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("provide ids or names of scripts to remove, or --all")
+	}
+
+	scripts, err := loadInitScripts()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		kept := scripts[:0]
+		for _, s := range scripts {
+			if strconv.Itoa(s.ID) != arg && s.Name != arg {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == len(scripts) {
+			return fmt.Errorf("no init script with id or name %q (see 'brow init-script list')", arg)
+		}
+		scripts = kept
+	}
+
+	if err := saveState(initScriptsState, scripts); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s\n", strings.Join(args, ", "))
+	return nil
+}
+
+// loadInitScripts returns the scripts registered with 'brow init-script add'
+func loadInitScripts() ([]initScript, error) {
+	var scripts []initScript
+	if err := loadState(initScriptsState, &scripts); err != nil {
+		return nil, err
+	}
+	return scripts, nil
+}
+
+// applyInitScripts registers the saved init scripts on the page's connection so documents
+// loaded while it is open run them
+func applyInitScripts(page *client.Page) error {
+	scripts, err := loadInitScripts()
+	if err != nil {
+		return err
+	}
+	for _, s := range scripts {
+		if _, err := page.AddInitScript(s.Source); err != nil {
+			return fmt.Errorf("init script %d (%s): %w", s.ID, s.Name, err)
+		}
+	}
+	return nil
+}
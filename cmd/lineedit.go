@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// errInterrupted is returned by readLine when Ctrl+C is pressed
+var errInterrupted = errors.New("interrupted")
+
+// lineEditor reads lines from a terminal with cursor movement and history, or plain lines
+// when stdin isn't a terminal
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	terminal bool
+	history  []string
+}
+
+// newLineEditor reads from stdin and echoes to stdout
+func newLineEditor(history []string) *lineEditor {
+	fd := int(os.Stdin.Fd())
+	return &lineEditor{
+		in:       bufio.NewReader(os.Stdin),
+		out:      os.Stdout,
+		fd:       fd,
+		terminal: term.IsTerminal(fd),
+		history:  history,
+	}
+}
+
+// addHistory records a line, skipping blanks and repeats of the previous line
+func (e *lineEditor) addHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(e.history); n > 0 && e.history[n-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// readLine shows prompt and returns the entered line without its newline
+// It returns io.EOF on Ctrl+D at an empty line or end of input, and errInterrupted on Ctrl+C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if !e.terminal {
+		line, err := e.in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	old, err := term.MakeRaw(e.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to configure terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(e.fd, old) }()
+
+	var line []rune
+	cursor := 0
+	entry := len(e.history) // history position being edited; len(history) is the new line
+	var pending []rune      // the new line, kept while browsing history
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(line))
+		if back := len(line) - cursor; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	recall := func(to int) {
+		if to < 0 || to > len(e.history) || to == entry {
+			return
+		}
+		if entry == len(e.history) {
+			pending = line
+		}
+		entry = to
+		if entry == len(e.history) {
+			line = pending
+		} else {
+			line = []rune(e.history[entry])
+		}
+		cursor = len(line)
+	}
+
+	// Raw mode also turns off output processing, so lines end with "\r\n"
+	fmt.Fprint(e.out, prompt)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case 3: // Ctrl+C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl+D
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if cursor < len(line) {
+				line = append(line[:cursor], line[cursor+1:]...)
+			}
+		case 127, 8: // Backspace
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case 1: // Ctrl+A
+			cursor = 0
+		case 5: // Ctrl+E
+			cursor = len(line)
+		case 2: // Ctrl+B
+			if cursor > 0 {
+				cursor--
+			}
+		case 6: // Ctrl+F
+			if cursor < len(line) {
+				cursor++
+			}
+		case 11: // Ctrl+K
+			line = line[:cursor]
+		case 21: // Ctrl+U
+			line = append([]rune{}, line[cursor:]...)
+			cursor = 0
+		case 23: // Ctrl+W
+			start := cursor
+			for start > 0 && unicode.IsSpace(line[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(line[start-1]) {
+				start--
+			}
+			line = append(line[:start], line[cursor:]...)
+			cursor = start
+		case 12: // Ctrl+L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case 16: // Ctrl+P
+			recall(entry - 1)
+		case 14: // Ctrl+N
+			recall(entry + 1)
+		case 27: // Escape sequence
+			switch e.readEscape() {
+			case "A":
+				recall(entry - 1)
+			case "B":
+				recall(entry + 1)
+			case "C":
+				if cursor < len(line) {
+					cursor++
+				}
+			case "D":
+				if cursor > 0 {
+					cursor--
+				}
+			case "H", "1~", "7~":
+				cursor = 0
+			case "F", "4~", "8~":
+				cursor = len(line)
+			case "3~":
+				if cursor < len(line) {
+					line = append(line[:cursor], line[cursor+1:]...)
+				}
+			}
+		case '\t':
+			line = append(line[:cursor], append([]rune("  "), line[cursor:]...)...)
+			cursor += 2
+		default:
+			if !unicode.IsPrint(r) {
+				continue
+			}
+			line = append(line[:cursor], append([]rune{r}, line[cursor:]...)...)
+			cursor++
+		}
+		redraw()
+	}
+}
+
+// readEscape reads the rest of a CSI or SS3 sequence after ESC and returns its parameters
+// and final byte, e.g. "A" for the up arrow or "3~" for Delete
+func (e *lineEditor) readEscape() string {
+	introducer, err := e.in.ReadByte()
+	if err != nil || (introducer != '[' && introducer != 'O') {
+		return ""
+	}
+
+	var seq []byte
+	for {
+		b, err := e.in.ReadByte()
+		if err != nil {
+			return ""
+		}
+		seq = append(seq, b)
+		if b >= 0x40 && b <= 0x7e {
+			return string(seq)
+		}
+	}
+}
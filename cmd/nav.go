@@ -3,32 +3,34 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	waitReady bool
+	waitReady     bool
+	noInitScripts bool
 )
 
 var navCmd = &cobra.Command{
 	Use:   "nav <url>",
 	Short: "Navigate to a URL",
-	Long:  `Navigates the browser to the specified URL and waits for the page to load.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runNav,
+	Long: `Navigates the browser to the specified URL and waits for the page to load.
+Scripts registered with 'brow init-script add' run in the new page before its own scripts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNav,
 }
 
 func init() {
 	rootCmd.AddCommand(navCmd)
 	navCmd.Flags().BoolVarP(&waitReady, "wait", "w", true, "Wait for page to be ready (default true)")
+	navCmd.Flags().BoolVar(&noInitScripts, "no-init-scripts", false, "Don't run scripts registered with 'brow init-script'")
 }
 
 func runNav(_ *cobra.Command, args []string) error {
 	url := args[0]
 
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -36,6 +38,12 @@ func runNav(_ *cobra.Command, args []string) error {
 	}
 	defer browser.Close()
 
+	if !noInitScripts {
+		if err := applyInitScripts(browser.Page()); err != nil {
+			return err
+		}
+	}
+
 	result, err := browser.Page().Navigate(url, waitReady)
 	if err != nil {
 		return err
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
 	"github.com/matejch/brow/pkg/operations"
 	"github.com/spf13/cobra"
@@ -14,21 +13,67 @@ var (
 	landscape bool
 	printBg   bool
 	pdfOutput string
+
+	paper          string
+	paperWidth     string
+	paperHeight    string
+	margin         string
+	marginTop      string
+	marginBottom   string
+	marginLeft     string
+	marginRight    string
+	pdfScale       float64
+	pageRanges     string
+	preferCSSSize  bool
+	headerTemplate string
+	footerTemplate string
+	pageNumbers    bool
+	taggedPDF      bool
+	outlinePDF     bool
 )
 
 var pdfCmd = &cobra.Command{
 	Use:   "pdf [output-file]",
 	Short: "Export the current page as PDF",
 	Long: `Generates a PDF from the current page.
-If no output file is specified, saves to 'output.pdf'.`,
+If no output file is specified, saves to 'output.pdf'.
+
+Lengths (--width, --height, --margin*) accept units: mm, cm, in, px or pt (bare numbers are inches).
+Header and footer templates are HTML; elements with the classes date, title, url,
+pageNumber and totalPages are filled in by Chrome. Leave room for them with --margin.`,
+	Example: `  brow pdf invoice.pdf --paper A4 --margin 15mm --page-numbers
+  brow pdf report.pdf --paper Letter --landscape --pages 1-3
+  brow pdf out.pdf --width 100mm --height 150mm --scale 0.8
+  brow pdf out.pdf --margin 20mm --header '<div style="font-size:9px" class="title"></div>'`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPDF,
 }
 
 func init() {
 	rootCmd.AddCommand(pdfCmd)
-	pdfCmd.Flags().BoolVarP(&landscape, "landscape", "l", false, "Use landscape orientation")
-	pdfCmd.Flags().BoolVarP(&printBg, "background", "b", true, "Print background graphics (default true)")
+	addPDFFlags(pdfCmd)
+}
+
+// addPDFFlags registers the PDF layout flags; shared with the render command
+func addPDFFlags(c *cobra.Command) {
+	c.Flags().BoolVarP(&landscape, "landscape", "l", false, "Use landscape orientation")
+	c.Flags().BoolVarP(&printBg, "background", "b", true, "Print background graphics (default true)")
+	c.Flags().StringVar(&paper, "paper", "", "Paper size: Letter, Legal, Tabloid, A3, A4 or A5")
+	c.Flags().StringVar(&paperWidth, "width", "", "Custom paper width (overrides --paper)")
+	c.Flags().StringVar(&paperHeight, "height", "", "Custom paper height (overrides --paper)")
+	c.Flags().StringVar(&margin, "margin", "", "Margin on all sides")
+	c.Flags().StringVar(&marginTop, "margin-top", "", "Top margin (overrides --margin)")
+	c.Flags().StringVar(&marginBottom, "margin-bottom", "", "Bottom margin (overrides --margin)")
+	c.Flags().StringVar(&marginLeft, "margin-left", "", "Left margin (overrides --margin)")
+	c.Flags().StringVar(&marginRight, "margin-right", "", "Right margin (overrides --margin)")
+	c.Flags().Float64Var(&pdfScale, "scale", 1, "Rendering scale from 0.1 to 2")
+	c.Flags().StringVar(&pageRanges, "pages", "", "Page ranges to print, e.g. '1-5, 8'")
+	c.Flags().BoolVar(&preferCSSSize, "prefer-css-page-size", false, "Use the page size from CSS @page rules")
+	c.Flags().StringVar(&headerTemplate, "header", "", "Header HTML template")
+	c.Flags().StringVar(&footerTemplate, "footer", "", "Footer HTML template")
+	c.Flags().BoolVar(&pageNumbers, "page-numbers", false, "Add a 'page / total' footer (unless --footer is set)")
+	c.Flags().BoolVar(&taggedPDF, "tagged", false, "Generate a tagged (accessible) PDF")
+	c.Flags().BoolVar(&outlinePDF, "outline", false, "Embed a document outline from the page headings")
 }
 
 func runPDF(_ *cobra.Command, args []string) error {
@@ -39,7 +84,12 @@ func runPDF(_ *cobra.Command, args []string) error {
 		pdfOutput = "output.pdf"
 	}
 
-	browser, err := client.New(&config.Config{
+	opts, err := pdfOptionsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -47,10 +97,7 @@ func runPDF(_ *cobra.Command, args []string) error {
 	}
 	defer browser.Close()
 
-	buf, err := browser.Page().PDF(operations.PDFOptions{
-		Landscape:       landscape,
-		PrintBackground: printBg,
-	})
+	buf, err := browser.Page().PDF(opts)
 	if err != nil {
 		return err
 	}
@@ -63,3 +110,58 @@ func runPDF(_ *cobra.Command, args []string) error {
 	fmt.Printf("PDF saved to: %s\n", pdfOutput)
 	return nil
 }
+
+// pdfOptionsFromFlags builds PDFOptions from the pdf command flags
+func pdfOptionsFromFlags() (operations.PDFOptions, error) {
+	opts := operations.PDFOptions{
+		Landscape:         landscape,
+		PrintBackground:   printBg,
+		Scale:             pdfScale,
+		PageRanges:        pageRanges,
+		PreferCSSPageSize: preferCSSSize,
+		HeaderTemplate:    headerTemplate,
+		FooterTemplate:    footerTemplate,
+		Tagged:            taggedPDF,
+		Outline:           outlinePDF,
+	}
+
+	if pageNumbers && opts.FooterTemplate == "" {
+		opts.FooterTemplate = operations.PageNumberFooter
+	}
+
+	if paper != "" {
+		size, err := operations.PaperSizeByName(paper)
+		if err != nil {
+			return opts, err
+		}
+		opts.Paper = size
+	}
+
+	// Each entry is applied in order, so specific margins override --margin
+	lengths := []struct {
+		value   string
+		targets []*float64
+	}{
+		{paperWidth, []*float64{&opts.Paper.Width}},
+		{paperHeight, []*float64{&opts.Paper.Height}},
+		{margin, []*float64{&opts.MarginTop, &opts.MarginBottom, &opts.MarginLeft, &opts.MarginRight}},
+		{marginTop, []*float64{&opts.MarginTop}},
+		{marginBottom, []*float64{&opts.MarginBottom}},
+		{marginLeft, []*float64{&opts.MarginLeft}},
+		{marginRight, []*float64{&opts.MarginRight}},
+	}
+	for _, l := range lengths {
+		if l.value == "" {
+			continue
+		}
+		inches, err := operations.ParseLength(l.value)
+		if err != nil {
+			return opts, err
+		}
+		for _, target := range l.targets {
+			*target = inches
+		}
+	}
+
+	return opts, nil
+}
@@ -1,15 +1,22 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
-	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
 	"github.com/spf13/cobra"
 )
 
 var (
-	xpath bool
+	xpath       bool
+	pickTimeout time.Duration
+	pickNoWait  bool
+	pickMulti   bool
+	pickSimilar bool
 )
 
 var pickCmd = &cobra.Command{
@@ -18,18 +25,50 @@ var pickCmd = &cobra.Command{
 	Long: `Injects an interactive overlay into the page that allows you to click on elements
 and get their CSS selector or XPath.
 
-The overlay highlights elements on hover and copies the selector on click.
-Press ESC to exit the picker mode.`,
+The overlay highlights elements on hover. The command waits until you click an element,
+then prints JSON with its selector, XPath, tag, text, attributes and bounding box
+(viewport coordinates). Pressing ESC or reaching --timeout exits with an error.
+
+Selectors prefer test ids (data-testid, data-cy, ...), stable ids, aria-label, name and
+text over positional chains, and are verified to be unique. The candidates field ranks
+alternatives (CSS, XPath, Playwright-style text="..." and role=...[name="..."]) with
+the number of elements each one matches. Only CSS candidates work with other brow
+commands such as 'brow click'; the others are meant for tools like Playwright.
+
+--multiple lets you click several elements (click again to deselect) and press Enter to
+finish; the output lists the elements plus a generalized selector matching all of them
+and its match count. --similar also outlines every element similar to the picked ones
+while picking, so clicking one product card shows the whole list; click more examples
+to refine the selector.
+
+The picker runs in every frame, including cross-site iframes, and picks elements inside
+open shadow roots. Their selectors step into iframes and shadow roots with " >>> ", e.g.
+'iframe#checkout >>> payment-form >>> input[name="card"]', which 'brow click' and
+'brow type' resolve. XPath can't step into iframes, so elements in iframes get CSS
+selectors only, even with --xpath, and their match counts are within the iframe. Boxes of
+elements in iframes are translated to the tab's viewport.
+
+Use --no-wait to only inject the picker into the top document; the selector can then be read with
+'brow eval --isolated window.__browPickedSelector'.`,
+	Example: `  brow pick
+  brow pick | jq -r .selector
+  brow pick | jq -r '.candidates[] | select(.type == "css" and .matches == 1) | .selector'
+  brow pick --xpath --timeout 30s
+  brow pick --similar | jq -r .selector`,
 	RunE: runPick,
 }
 
 func init() {
 	rootCmd.AddCommand(pickCmd)
 	pickCmd.Flags().BoolVarP(&xpath, "xpath", "x", false, "Return XPath instead of CSS selector")
+	pickCmd.Flags().DurationVarP(&pickTimeout, "timeout", "t", 2*time.Minute, "How long to wait for a pick (0 waits forever)")
+	pickCmd.Flags().BoolVar(&pickNoWait, "no-wait", false, "Inject the picker and return immediately")
+	pickCmd.Flags().BoolVarP(&pickMulti, "multiple", "m", false, "Pick several elements, finish with Enter")
+	pickCmd.Flags().BoolVarP(&pickSimilar, "similar", "s", false, "Highlight and generalize to similar elements (implies --multiple)")
 }
 
 func runPick(_ *cobra.Command, _ []string) error {
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -37,17 +76,45 @@ func runPick(_ *cobra.Command, _ []string) error {
 	}
 	defer browser.Close()
 
-	if err := browser.Page().InjectPicker(xpath); err != nil {
+	opts := operations.PickOptions{
+		XPath:    xpath,
+		Timeout:  pickTimeout,
+		Multiple: pickMulti,
+		Similar:  pickSimilar,
+	}
+
+	if pickNoWait {
+		if err := browser.Page().InjectPickerWithOptions(opts); err != nil {
+			return err
+		}
+
+		fmt.Println("Element picker activated!")
+		fmt.Println("Hover over elements to highlight, click to select, press ESC to exit.")
+		fmt.Println("")
+		fmt.Println("After selecting an element, run:")
+		fmt.Println("  brow eval --isolated 'window.__browPickedSelector'")
+		fmt.Println("")
+		fmt.Println("To get the selected element's selector.")
+		return nil
+	}
+
+	var result interface{}
+	if pickMulti || pickSimilar {
+		fmt.Fprintln(os.Stderr, "Click elements to select (again to deselect), press Enter to finish or ESC to cancel.")
+		result, err = browser.Page().PickElements(opts)
+	} else {
+		fmt.Fprintln(os.Stderr, "Hover over elements to highlight, click to select, press ESC to cancel.")
+		result, err = browser.Page().Pick(opts)
+	}
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("Element picker activated!")
-	fmt.Println("Hover over elements to highlight, click to select, press ESC to exit.")
-	fmt.Println("")
-	fmt.Println("After selecting an element, run:")
-	fmt.Println("  brow eval 'window.__browPickedSelector'")
-	fmt.Println("")
-	fmt.Println("To get the selected element's selector.")
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format element: %w", err)
+	}
 
+	fmt.Println(string(output))
 	return nil
 }
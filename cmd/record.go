@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordOut       string
+	recordFPS       int
+	recordMaxWidth  int
+	recordMaxHeight int
+	recordQuality   int
+)
+
+// recordingStateName is the state file written by a running recorder
+const recordingStateName = "recording"
+
+// recordingState describes the background recorder started by 'brow record start'
+type recordingState struct {
+	PID     int       `json:"pid"`
+	Output  string    `json:"output"`
+	Started time.Time `json:"started"`
+}
+
+// recordedFrame is an entry in frames.json when recording to a directory
+type recordedFrame struct {
+	File      string    `json:"file"`
+	Timestamp time.Time `json:"timestamp"`
+	OffsetMs  int64     `json:"offsetMs"`
+}
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record the page as timestamped frames or an animated GIF",
+	Long: `Records what happens in the current page using the Chrome screencast API.
+
+'brow record start' launches a background recorder and returns immediately, so you can keep
+running other brow commands. 'brow record stop' ends the recording and writes the output.
+
+If --out ends in .gif, an animated GIF is written. Otherwise --out is a directory that receives
+one JPEG per frame plus frames.json listing each frame's timestamp.`,
+}
+
+var recordStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start recording in the background",
+	Args:  cobra.NoArgs,
+	RunE:  runRecordStart,
+}
+
+var recordStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the background recording and save it",
+	Args:  cobra.NoArgs,
+	RunE:  runRecordStop,
+}
+
+// recordRunCmd is the recorder process itself; it records in the foreground until interrupted
+var recordRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Record in the foreground until interrupted",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runRecordRun,
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+	recordCmd.AddCommand(recordStartCmd, recordStopCmd, recordRunCmd)
+
+	for _, c := range []*cobra.Command{recordStartCmd, recordRunCmd} {
+		c.Flags().StringVarP(&recordOut, "out", "o", "recording", "Output directory, or a .gif file")
+		c.Flags().IntVar(&recordFPS, "fps", 5, "Maximum frames per second")
+		c.Flags().IntVar(&recordMaxWidth, "max-width", 0, "Maximum frame width in pixels (0 = viewport size)")
+		c.Flags().IntVar(&recordMaxHeight, "max-height", 0, "Maximum frame height in pixels (0 = viewport size)")
+		c.Flags().IntVar(&recordQuality, "quality", 80, "JPEG quality of captured frames (1-100)")
+	}
+}
+
+func runRecordStart(_ *cobra.Command, _ []string) error {
+	var existing recordingState
+	if err := loadState(recordingStateName, &existing); err != nil {
+		return err
+	}
+	if existing.PID != 0 && processAlive(existing.PID) {
+		return fmt.Errorf("a recording is already running (PID: %d), run 'brow record stop' first", existing.PID)
+	}
+
+	output, err := filepath.Abs(recordOut)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate brow executable: %w", err)
+	}
+
+	logPath, err := statePath("record.log")
+	if err != nil {
+		return err
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create recorder log: %w", err)
+	}
+	defer logFile.Close()
+
+	recorder := exec.Command(exe,
+		"--port", strconv.Itoa(config.ResolvePort(Port)),
+		"record", "run",
+		"--out", output,
+		"--fps", strconv.Itoa(recordFPS),
+		"--max-width", strconv.Itoa(recordMaxWidth),
+		"--max-height", strconv.Itoa(recordMaxHeight),
+		"--quality", strconv.Itoa(recordQuality),
+	)
+
+	// Detach like 'brow start' does for Chrome so the recorder outlives this command
+	recorder.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	recorder.Stdout = logFile
+	recorder.Stderr = logFile
+
+	if err := recorder.Start(); err != nil {
+		return fmt.Errorf("failed to start recorder: %w", err)
+	}
+	pid := recorder.Process.Pid
+	if err := recorder.Process.Release(); err != nil {
+		return fmt.Errorf("failed to release recorder process: %w", err)
+	}
+
+	// Wait until the recorder reports that the screencast is running
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var state recordingState
+		if err := loadState(recordingStateName, &state); err != nil {
+			return err
+		}
+		if state.PID == pid {
+			fmt.Printf("Recording started (PID: %d)\n", pid)
+			fmt.Printf("Output: %s\n", output)
+			fmt.Println("Run 'brow record stop' to finish.")
+			return nil
+		}
+		if !processAlive(pid) {
+			return fmt.Errorf("recorder exited: %s", readLog(logPath))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return fmt.Errorf("recorder did not start within 10s, see %s", logPath)
+}
+
+func runRecordStop(_ *cobra.Command, _ []string) error {
+	var state recordingState
+	if err := loadState(recordingStateName, &state); err != nil {
+		return err
+	}
+	if state.PID == 0 {
+		return fmt.Errorf("no recording in progress")
+	}
+
+	logPath, err := statePath("record.log")
+	if err != nil {
+		return err
+	}
+
+	if !processAlive(state.PID) {
+		if err := removeState(recordingStateName); err != nil {
+			return err
+		}
+		return fmt.Errorf("recorder is no longer running: %s", readLog(logPath))
+	}
+
+	if err := syscall.Kill(state.PID, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to stop recorder: %w", err)
+	}
+
+	// Encoding a long GIF can take a while
+	deadline := time.Now().Add(2 * time.Minute)
+	for processAlive(state.PID) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("recorder (PID: %d) did not exit, see %s", state.PID, logPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// The recorder removes its state file once the output is written
+	var after recordingState
+	if err := loadState(recordingStateName, &after); err != nil {
+		return err
+	}
+	if after.PID == state.PID {
+		if err := removeState(recordingStateName); err != nil {
+			return err
+		}
+		return fmt.Errorf("recorder failed: %s", readLog(logPath))
+	}
+
+	fmt.Println(readLog(logPath))
+	return nil
+}
+
+func runRecordRun(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	asGIF := strings.EqualFold(filepath.Ext(recordOut), ".gif")
+
+	opts := operations.ScreencastOptions{
+		MaxFPS:    recordFPS,
+		MaxWidth:  recordMaxWidth,
+		MaxHeight: recordMaxHeight,
+		Quality:   recordQuality,
+	}
+
+	// Directory output streams frames to disk instead of holding them in memory
+	var index []recordedFrame
+	if !asGIF {
+		if err := os.MkdirAll(recordOut, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		opts.OnFrame = func(frame operations.ScreencastFrame) error {
+			name := fmt.Sprintf("frame-%05d.jpg", len(index)+1)
+			if err := os.WriteFile(filepath.Join(recordOut, name), frame.Data, 0644); err != nil {
+				return fmt.Errorf("failed to write frame: %w", err)
+			}
+			var offset int64
+			if len(index) > 0 {
+				offset = frame.Timestamp.Sub(index[0].Timestamp).Milliseconds()
+			}
+			index = append(index, recordedFrame{File: name, Timestamp: frame.Timestamp, OffsetMs: offset})
+			return nil
+		}
+	}
+
+	screencast, err := browser.Page().StartScreencast(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := saveState(recordingStateName, recordingState{
+		PID:     os.Getpid(),
+		Output:  recordOut,
+		Started: time.Now(),
+	}); err != nil {
+		_, _ = screencast.Stop()
+		return err
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	frames, err := screencast.Stop()
+	if err != nil {
+		return err
+	}
+
+	if asGIF {
+		data, err := operations.EncodeGIF(frames)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(recordOut, data, 0644); err != nil {
+			return fmt.Errorf("failed to write GIF: %w", err)
+		}
+		fmt.Printf("Recording saved to: %s (%d frames)\n", recordOut, len(frames))
+	} else {
+		manifest, err := json.MarshalIndent(index, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format frame index: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(recordOut, "frames.json"), manifest, 0644); err != nil {
+			return fmt.Errorf("failed to write frame index: %w", err)
+		}
+		fmt.Printf("Recording saved to: %s (%d frames)\n", recordOut, len(index))
+	}
+
+	return removeState(recordingStateName)
+}
+
+// processAlive reports whether a process with the given PID is running
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+// readLog returns the trimmed contents of the recorder log for error messages
+func readLog(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("see %s", path)
+	}
+	return strings.TrimSpace(string(data))
+}
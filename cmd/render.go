@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderPDF      string
+	renderPNG      string
+	renderFullPage bool
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render <file.html | ->",
+	Short: "Render a local HTML file to PDF or PNG",
+	Long: `Renders a local HTML file (or HTML from stdin with "-") in a temporary tab,
+waits for fonts and images to load, writes a PDF and/or screenshot, and closes the tab.
+The current tab is left untouched and no web server is needed.
+
+Files are loaded through a file:// URL so relative images and stylesheets resolve.
+HTML from stdin has no base URL; use absolute or data: URLs for its assets.
+
+All 'brow pdf' layout flags (--paper, --margin, --header, ...) apply to --pdf output.
+If neither --pdf nor --png is given, the PDF is written to output.pdf.`,
+	Example: `  brow render report.html --pdf report.pdf --paper A4 --margin 15mm --page-numbers
+  brow render card.html --png card.png --full-page
+  ./gen-report | brow render - --pdf report.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRender,
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVar(&renderPDF, "pdf", "", "Write a PDF to this file")
+	renderCmd.Flags().StringVar(&renderPNG, "png", "", "Write a screenshot to this file")
+	renderCmd.Flags().BoolVarP(&renderFullPage, "full-page", "f", false, "Capture the full page for --png")
+	addPDFFlags(renderCmd)
+}
+
+func runRender(_ *cobra.Command, args []string) error {
+	if renderPDF == "" && renderPNG == "" {
+		renderPDF = "output.pdf"
+	}
+
+	var opts operations.RenderOptions
+	if renderPDF != "" {
+		pdfOpts, err := pdfOptionsFromFlags()
+		if err != nil {
+			return err
+		}
+		opts.PDF = &pdfOpts
+	}
+	if renderPNG != "" {
+		opts.Screenshot = &operations.ScreenshotOptions{
+			FullPage: renderFullPage,
+			Quality:  100,
+		}
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port:    config.ResolvePort(Port),
+		Timeout: config.DefaultTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	var result *operations.RenderResult
+	if args[0] == "-" {
+		html, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read HTML from stdin: %w", err)
+		}
+		result, err = browser.RenderHTML(string(html), opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		result, err = browser.RenderFile(args[0], opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if renderPDF != "" {
+		if err := os.WriteFile(renderPDF, result.PDF, 0644); err != nil {
+			return fmt.Errorf("failed to write PDF to file: %w", err)
+		}
+		fmt.Printf("PDF saved to: %s\n", renderPDF)
+	}
+	if renderPNG != "" {
+		if err := os.WriteFile(renderPNG, result.Screenshot, 0644); err != nil {
+			return fmt.Errorf("failed to write screenshot to file: %w", err)
+		}
+		fmt.Printf("Screenshot saved to: %s\n", renderPNG)
+	}
+
+	return nil
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matejch/brow/pkg/client"
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+// replHistoryState is the state file holding the REPL's input history
+const replHistoryState = "repl-history"
+
+// replHistoryLimit is the number of history lines kept between sessions
+const replHistoryLimit = 1000
+
+const replHelp = `JavaScript is evaluated in the current tab, like the DevTools console: top-level await
+works, promises are awaited and let/const can be redeclared. Unfinished input (an open
+bracket, string template or comment) continues on the next line.
+
+  .nav <url>           Navigate the current tab
+  .shot [file] [-f]    Save a screenshot (default screenshot.png; -f for the full page)
+  .tabs [index]        List tabs, or switch to the tab at index
+  .cookies [domain]    Print cookies, optionally only for a domain
+  .pick [-m]           Pick an element (or several with -m) and print its selector
+  .isolated            Toggle evaluation in brow's isolated world
+  .clear               Discard unfinished multi-line input
+  .help                Show this help
+  .exit                Leave (or press Ctrl+D)
+
+Ctrl+C interrupts a running evaluation or discards the current input.`
+
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Interactive JavaScript console for the current tab",
+	Long: `Starts an interactive console connected to the current tab over a single connection.
+
+` + replHelp + `
+
+Results are printed as JSON, keeping DOM nodes, Map, Set, BigInt and undefined (see
+'brow eval --rich'). Input history is saved per debugging port. Scripts registered with
+'brow init-script' run on pages loaded during the session.`,
+	Args: cobra.NoArgs,
+	RunE: runRepl,
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+}
+
+// replSession is the state of a running REPL
+type replSession struct {
+	browser  *client.Browser
+	page     *client.Page
+	tab      int
+	isolated bool
+	editor   *lineEditor
+	signals  chan os.Signal
+}
+
+func runRepl(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	var history []string
+	if err := loadState(replHistoryState, &history); err != nil {
+		return err
+	}
+
+	s := &replSession{
+		browser: browser,
+		page:    browser.Page(),
+		editor:  newLineEditor(history),
+		signals: make(chan os.Signal, 1),
+	}
+	if err := applyInitScripts(s.page); err != nil {
+		return err
+	}
+
+	// Ctrl+C interrupts evaluations instead of ending the session; while a line is being
+	// read the terminal is in raw mode and the editor handles it
+	signal.Notify(s.signals, os.Interrupt)
+	defer signal.Stop(s.signals)
+
+	if s.editor.terminal {
+		fmt.Println("brow repl - type .help for commands, Ctrl+D to exit")
+	}
+	defer s.saveHistory()
+
+	var buffer []string
+	for {
+		prompt := "> "
+		if len(buffer) > 0 {
+			prompt = "... "
+		}
+		line, err := s.editor.readLine(prompt)
+		if errors.Is(err, errInterrupted) {
+			if len(buffer) == 0 {
+				fmt.Println("(To exit, press Ctrl+D or type .exit)")
+			}
+			buffer = nil
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.editor.addHistory(line)
+
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, ".") && (len(buffer) == 0 || trimmed == ".clear") {
+			buffer = nil
+			if trimmed == ".exit" {
+				return nil
+			}
+			if err := s.command(trimmed); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			continue
+		}
+
+		buffer = append(buffer, line)
+		script := strings.Join(buffer, "\n")
+		if strings.TrimSpace(script) == "" {
+			buffer = nil
+			continue
+		}
+		if errors.Is(s.page.CheckSyntax(script), operations.ErrIncompleteScript) {
+			continue
+		}
+		buffer = nil
+
+		if err := s.eval(script); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// eval evaluates script and prints its result; Ctrl+C terminates the script
+func (s *replSession) eval(script string) error {
+	// Like the DevTools console, treat {...} as an object literal rather than a block
+	if trimmed := strings.TrimSpace(script); strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+		if s.page.CheckSyntax("("+script+")") == nil {
+			script = "(" + script + ")"
+		}
+	}
+
+	var result interface{}
+	err := s.interruptible(func(page *client.Page) error {
+		var err error
+		result, err = page.EvalWithOptions(script, operations.EvalOptions{
+			AwaitPromise: true,
+			Rich:         true,
+			ReplMode:     true,
+			Isolated:     s.isolated,
+		})
+		return err
+	}, func() {
+		_ = s.page.InterruptScript()
+	})
+	if err != nil {
+		return err
+	}
+	return printReplValue(result)
+}
+
+// interruptible runs fn with a page whose operations are cancelled by Ctrl+C, calling
+// onInterrupt after cancelling
+func (s *replSession) interruptible(fn func(page *client.Page) error, onInterrupt func()) error {
+	ctx, cancel := context.WithCancel(s.page.Context())
+	defer cancel()
+
+	// Drop a Ctrl+C pressed before this operation started
+	select {
+	case <-s.signals:
+	default:
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	interrupted := make(chan struct{})
+	go func() {
+		select {
+		case <-s.signals:
+			cancel()
+			if onInterrupt != nil {
+				onInterrupt()
+			}
+			close(interrupted)
+		case <-done:
+		}
+	}()
+
+	err := fn(s.page.WithContext(ctx))
+	select {
+	case <-interrupted:
+		return fmt.Errorf("interrupted")
+	default:
+		return err
+	}
+}
+
+// command runs a dot-command
+func (s *replSession) command(line string) error {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case ".help":
+		fmt.Println(replHelp)
+		return nil
+	case ".clear":
+		return nil
+	case ".isolated":
+		s.isolated = !s.isolated
+		if s.isolated {
+			fmt.Println("Evaluating in brow's isolated world (page globals are not visible)")
+		} else {
+			fmt.Println("Evaluating in the page")
+		}
+		return nil
+	case ".nav":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: .nav <url>")
+		}
+		return s.interruptible(func(page *client.Page) error {
+			result, err := page.Navigate(args[0], true)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Navigated to: %s\nPage title: %s\n", args[0], result.Title)
+			return nil
+		}, nil)
+	case ".shot":
+		return s.screenshot(args)
+	case ".tabs":
+		return s.tabs(args)
+	case ".cookies":
+		if len(args) > 1 {
+			return fmt.Errorf("usage: .cookies [domain]")
+		}
+		domain := ""
+		if len(args) == 1 {
+			domain = args[0]
+		}
+		return s.interruptible(func(page *client.Page) error {
+			cookies, err := page.GetCookies(domain)
+			if err != nil {
+				return err
+			}
+			return printReplValue(cookies)
+		}, nil)
+	case ".pick":
+		return s.pick(args)
+	}
+	return fmt.Errorf("unknown command %s (see .help)", name)
+}
+
+// screenshot implements .shot [file] [-f]
+func (s *replSession) screenshot(args []string) error {
+	file := "screenshot.png"
+	opts := operations.ScreenshotOptions{Quality: 100}
+	for _, arg := range args {
+		if arg == "-f" || arg == "--full-page" {
+			opts.FullPage = true
+		} else {
+			file = arg
+		}
+	}
+
+	return s.interruptible(func(page *client.Page) error {
+		buf, err := page.Screenshot(opts)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, buf, 0644); err != nil {
+			return fmt.Errorf("failed to write screenshot to file: %w", err)
+		}
+		fmt.Printf("Screenshot saved to: %s\n", file)
+		return nil
+	}, nil)
+}
+
+// tabs implements .tabs [index]; titles and URLs are read live since tabs navigate
+func (s *replSession) tabs(args []string) error {
+	if len(args) == 1 {
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("usage: .tabs [index]")
+		}
+		page, err := s.browser.TabByIndex(index)
+		if err != nil {
+			return err
+		}
+		if err := applyInitScripts(page); err != nil {
+			return err
+		}
+		s.page, s.tab = page, index
+		fmt.Printf("Switched to tab %d\n", index)
+		return nil
+	}
+
+	tabs, err := s.browser.Tabs()
+	if err != nil {
+		return err
+	}
+	for _, tab := range tabs {
+		title, url := tab.Title, tab.URL
+		if page, err := s.browser.TabByIndex(tab.Index); err == nil {
+			ctx, cancel := context.WithTimeout(page.Context(), 2*time.Second)
+			var live struct {
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			if page.WithContext(ctx).EvalInto(`({title: document.title, url: location.href})`, &live) == nil {
+				title, url = live.Title, live.URL
+			}
+			cancel()
+		}
+
+		marker := " "
+		if tab.Index == s.tab {
+			marker = "*"
+		}
+		fmt.Printf("%s %d\t%s\t%s\n", marker, tab.Index, title, url)
+	}
+	return nil
+}
+
+// pick implements .pick [-m]
+func (s *replSession) pick(args []string) error {
+	multiple := len(args) == 1 && (args[0] == "-m" || args[0] == "--multiple")
+	if len(args) > 0 && !multiple {
+		return fmt.Errorf("usage: .pick [-m]")
+	}
+
+	opts := operations.PickOptions{Timeout: 2 * time.Minute, Multiple: multiple}
+	return s.interruptible(func(page *client.Page) error {
+		if multiple {
+			fmt.Println("Click elements to select (again to deselect), press Enter in the page to finish.")
+			group, err := page.PickElements(opts)
+			if err != nil {
+				return err
+			}
+			fmt.Println(group.Selector)
+			return nil
+		}
+
+		fmt.Println("Click an element in the page (ESC to cancel).")
+		picked, err := page.Pick(opts)
+		if err != nil {
+			return err
+		}
+		fmt.Println(picked.Selector)
+		return nil
+	}, nil)
+}
+
+// saveHistory keeps the latest input lines for the next session
+func (s *replSession) saveHistory() {
+	history := s.editor.history
+	if len(history) > replHistoryLimit {
+		history = history[len(history)-replHistoryLimit:]
+	}
+	if err := saveState(replHistoryState, history); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// printReplValue prints a result as indented JSON
+func printReplValue(v interface{}) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format result as JSON: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -33,4 +33,5 @@ func init() {
 
 	// Add persistent flag for Chrome debugging port
 	rootCmd.PersistentFlags().IntVar(&Port, "port", 0, "Chrome remote debugging port (default 9222, or set BROW_DEBUG_PORT env var)")
+	rootCmd.PersistentFlags().StringVar(&dialogFlag, "dialog", "", "Answer JavaScript dialogs: accept, accept=<text>, dismiss or none (see 'brow dialog')")
 }
@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"image"
+	"math"
 	"os"
 
 	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/imagediff"
 	"github.com/matejch/brow/pkg/operations"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +19,14 @@ var (
 	fullPage   bool
 	outputFile string
 	base64Out  bool
+	annotate   bool
+
+	compareWith    string
+	diffOut        string
+	threshold      float64
+	maxDiffRatio   float64
+	ignoreSel      []string
+	updateBaseline bool
 )
 
 var screenshotCmd = &cobra.Command{
@@ -22,7 +34,17 @@ var screenshotCmd = &cobra.Command{
 	Short: "Capture a screenshot of the current page",
 	Long: `Captures a screenshot of the current page.
 If no output file is specified, outputs base64-encoded image data.
-Use --full-page to capture the entire page instead of just the viewport.`,
+Use --full-page to capture the entire page instead of just the viewport.
+
+Use --annotate to overlay numbered labels on interactive elements (links, buttons, inputs)
+and print a JSON legend mapping each number to its selector, role, text and bounding box.
+Numbers from the last legend can be used as selectors in click and type, e.g. 'brow click "#12"'.
+
+Use --compare to check the page against a baseline image for visual regression testing.
+The command exits non-zero when the screenshot differs; --diff-out writes an image
+highlighting the differences and --update overwrites the baseline with the current page.
+
+Use --frame to capture only an iframe (see 'brow frames').`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runScreenshot,
 }
@@ -31,6 +53,15 @@ func init() {
 	rootCmd.AddCommand(screenshotCmd)
 	screenshotCmd.Flags().BoolVarP(&fullPage, "full-page", "f", false, "Capture full page (not just viewport)")
 	screenshotCmd.Flags().BoolVarP(&base64Out, "base64", "b", false, "Output base64-encoded image data")
+	screenshotCmd.Flags().BoolVarP(&annotate, "annotate", "a", false, "Label interactive elements and print a JSON legend")
+	screenshotCmd.Flags().StringVar(&compareWith, "compare", "", "Compare against a baseline image and fail on mismatch")
+	screenshotCmd.Flags().StringVar(&diffOut, "diff-out", "", "Write an image highlighting differences (with --compare)")
+	screenshotCmd.Flags().Float64Var(&threshold, "threshold", imagediff.DefaultThreshold,
+		"Per-pixel color sensitivity from 0 (exact) to 1 (with --compare)")
+	screenshotCmd.Flags().Float64Var(&maxDiffRatio, "max-diff", 0, "Fraction of differing pixels tolerated (with --compare)")
+	screenshotCmd.Flags().StringArrayVar(&ignoreSel, "ignore", nil, "CSS selector of a region to ignore (repeatable, with --compare)")
+	screenshotCmd.Flags().BoolVar(&updateBaseline, "update", false, "Write the current screenshot as the new baseline (with --compare)")
+	addFrameFlag(screenshotCmd)
 }
 
 func runScreenshot(_ *cobra.Command, args []string) error {
@@ -39,7 +70,17 @@ func runScreenshot(_ *cobra.Command, args []string) error {
 		outputFile = args[0]
 	}
 
-	browser, err := client.New(&config.Config{
+	if annotate && base64Out {
+		return fmt.Errorf("--annotate prints a JSON legend and cannot be combined with --base64")
+	}
+	if annotate && compareWith != "" {
+		return fmt.Errorf("--annotate cannot be combined with --compare")
+	}
+	if frameSpec != "" && (annotate || fullPage || len(ignoreSel) > 0) {
+		return fmt.Errorf("--frame cannot be combined with --annotate, --full-page or --ignore")
+	}
+
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -47,10 +88,21 @@ func runScreenshot(_ *cobra.Command, args []string) error {
 	}
 	defer browser.Close()
 
-	buf, err := browser.Page().Screenshot(operations.ScreenshotOptions{
+	opts := operations.ScreenshotOptions{
 		FullPage: fullPage,
 		Quality:  100,
-	})
+		Frame:    frameSpec,
+	}
+
+	if annotate {
+		return runAnnotatedScreenshot(browser.Page(), opts)
+	}
+
+	if compareWith != "" {
+		return runCompareScreenshot(browser.Page(), opts)
+	}
+
+	buf, err := browser.Page().Screenshot(opts)
 	if err != nil {
 		return err
 	}
@@ -74,3 +126,108 @@ func runScreenshot(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runAnnotatedScreenshot saves a labelled screenshot, prints its legend as JSON
+// and remembers the legend so "#N" references resolve in later commands
+func runAnnotatedScreenshot(page *client.Page, opts operations.ScreenshotOptions) error {
+	buf, marks, err := page.AnnotatedScreenshot(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := saveState(legendState, marks); err != nil {
+		return err
+	}
+
+	output, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format legend: %w", err)
+	}
+
+	target := outputFile
+	if target == "" {
+		target = "screenshot.png"
+	}
+	if err := os.WriteFile(target, buf, 0644); err != nil {
+		return fmt.Errorf("failed to write screenshot to file: %w", err)
+	}
+
+	// Keep stdout pure JSON so the legend can be piped straight into other tools
+	fmt.Fprintf(os.Stderr, "Screenshot saved to: %s\n", target)
+	fmt.Println(string(output))
+	return nil
+}
+
+// runCompareScreenshot compares the current page against the --compare baseline
+// and returns an error when they differ so the command exits non-zero
+func runCompareScreenshot(page *client.Page, opts operations.ScreenshotOptions) error {
+	buf, err := page.Screenshot(opts)
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, buf, 0644); err != nil {
+			return fmt.Errorf("failed to write screenshot to file: %w", err)
+		}
+	}
+
+	if updateBaseline {
+		if err := os.WriteFile(compareWith, buf, 0644); err != nil {
+			return fmt.Errorf("failed to write baseline: %w", err)
+		}
+		fmt.Printf("Baseline updated: %s\n", compareWith)
+		return nil
+	}
+
+	baseline, err := os.ReadFile(compareWith)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline (use --update to create it): %w", err)
+	}
+
+	var ignore []image.Rectangle
+	if len(ignoreSel) > 0 {
+		boxes, err := page.ElementRegions(ignoreSel, opts.FullPage)
+		if err != nil {
+			return err
+		}
+		for _, box := range boxes {
+			ignore = append(ignore, image.Rect(
+				int(math.Floor(box.X)), int(math.Floor(box.Y)),
+				int(math.Ceil(box.X+box.Width)), int(math.Ceil(box.Y+box.Height)),
+			))
+		}
+	}
+
+	result, err := imagediff.CompareBytes(baseline, buf, imagediff.Options{
+		Threshold:    threshold,
+		MaxDiffRatio: maxDiffRatio,
+		Ignore:       ignore,
+	})
+	if err != nil {
+		return err
+	}
+
+	if diffOut != "" {
+		diffPNG, err := result.EncodePNG()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(diffOut, diffPNG, 0644); err != nil {
+			return fmt.Errorf("failed to write diff image: %w", err)
+		}
+		fmt.Printf("Diff saved to: %s\n", diffOut)
+	}
+
+	if !result.SizeMatch {
+		return fmt.Errorf("screenshot size differs from baseline %s", compareWith)
+	}
+	if !result.Match {
+		return fmt.Errorf("screenshot differs from baseline %s: %d pixels (%.2f%%)",
+			compareWith, result.DiffPixels, result.DiffRatio*100)
+	}
+
+	fmt.Printf("Screenshot matches baseline: %s (%d pixels differ, %.2f%%)\n",
+		compareWith, result.DiffPixels, result.DiffRatio*100)
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/matejch/brow/pkg/operations"
+)
+
+// markRefPattern matches "#12"-style references to the last annotated screenshot.
+// CSS ids cannot start with a digit, so these never collide with real selectors.
+var markRefPattern = regexp.MustCompile(`^#(\d+)$`)
+
+// legendState is the state file holding the legend of the last annotated screenshot
+const legendState = "legend"
+
+// resolveSelector turns a "#N" reference into the selector recorded by `brow screenshot --annotate`
+// Any other input is returned unchanged
+func resolveSelector(selector string) (string, error) {
+	match := markRefPattern.FindStringSubmatch(selector)
+	if match == nil {
+		return selector, nil
+	}
+
+	index, err := strconv.Atoi(match[1])
+	if err != nil {
+		return "", err
+	}
+
+	var marks []operations.Mark
+	if err := loadState(legendState, &marks); err != nil {
+		return "", err
+	}
+
+	mark, err := operations.FindMark(marks, index)
+	if err != nil {
+		return "", err
+	}
+	return mark.Selector, nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matejch/brow/pkg/client"
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchPDF         bool
+	batchPNG         bool
+	batchOut         string
+	batchConcurrency int
+	batchRetries     int
+	batchTimeout     time.Duration
+	batchFullPage    bool
+)
+
+// snapshotEntry is one URL's record in manifest.json
+type snapshotEntry struct {
+	URL        string    `json:"url"`
+	Status     string    `json:"status"`
+	Title      string    `json:"title,omitempty"`
+	PDF        string    `json:"pdf,omitempty"`
+	PNG        string    `json:"png,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Attempts   int       `json:"attempts"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+var snapshotBatchCmd = &cobra.Command{
+	Use:   "snapshot-batch <urls.txt | ->",
+	Short: "Save PDFs and/or screenshots for a list of URLs in parallel",
+	Long: `Reads URLs (one per line, '#' comments allowed) from a file or stdin and saves
+a PDF and/or PNG of each page, using several tabs in parallel. Each URL is loaded in its
+own temporary tab, so the current tab is left untouched.
+
+Files are named deterministically from the URL (host, path and a short hash), so
+re-running a batch overwrites the same files. A manifest.json in the output directory
+records status, title, output files, attempts and timing for every URL.
+
+All 'brow pdf' layout flags (--paper, --margin, ...) apply to PDF output.
+The command exits non-zero if any URL still fails after retries.`,
+	Example: `  brow snapshot-batch urls.txt --pdf --png --out archive/ --concurrency 4
+  brow snapshot-batch urls.txt --png --full-page --retries 3 --timeout 90s`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotBatch,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotBatchCmd)
+	snapshotBatchCmd.Flags().BoolVar(&batchPDF, "pdf", false, "Save a PDF of each page (default if neither --pdf nor --png)")
+	snapshotBatchCmd.Flags().BoolVar(&batchPNG, "png", false, "Save a screenshot of each page")
+	snapshotBatchCmd.Flags().StringVarP(&batchOut, "out", "o", "snapshots", "Output directory")
+	snapshotBatchCmd.Flags().IntVarP(&batchConcurrency, "concurrency", "c", 4, "Number of tabs to use in parallel")
+	snapshotBatchCmd.Flags().IntVar(&batchRetries, "retries", 2, "Retries per URL after a failure")
+	snapshotBatchCmd.Flags().DurationVar(&batchTimeout, "timeout", config.DefaultTimeout, "Timeout per attempt")
+	snapshotBatchCmd.Flags().BoolVarP(&batchFullPage, "full-page", "f", false, "Capture the full page for --png")
+	addPDFFlags(snapshotBatchCmd)
+}
+
+func runSnapshotBatch(_ *cobra.Command, args []string) error {
+	urls, err := readURLList(args[0])
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("no URLs found in %s", args[0])
+	}
+
+	if !batchPDF && !batchPNG {
+		batchPDF = true
+	}
+	if batchConcurrency < 1 {
+		batchConcurrency = 1
+	}
+
+	var opts operations.RenderOptions
+	if batchPDF {
+		pdfOpts, err := pdfOptionsFromFlags()
+		if err != nil {
+			return err
+		}
+		opts.PDF = &pdfOpts
+	}
+	if batchPNG {
+		opts.Screenshot = &operations.ScreenshotOptions{
+			FullPage: batchFullPage,
+			Quality:  100,
+		}
+	}
+
+	if err := os.MkdirAll(batchOut, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port:    config.ResolvePort(Port),
+		Timeout: batchTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	manifest := make([]snapshotEntry, len(urls))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	done := 0
+
+	for w := 0; w < batchConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				manifest[i] = snapshotURL(browser, urls[i], opts)
+
+				progressMu.Lock()
+				done++
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s %s (%.1fs)\n",
+					done, len(urls), manifest[i].Status, urls[i], float64(manifest[i].DurationMs)/1000)
+				progressMu.Unlock()
+			}
+		}()
+	}
+
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format manifest: %w", err)
+	}
+	manifestPath := filepath.Join(batchOut, "manifest.json")
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	failed := 0
+	for _, entry := range manifest {
+		if entry.Status != "ok" {
+			failed++
+		}
+	}
+
+	fmt.Printf("Snapshots saved to: %s (%d ok, %d failed)\n", batchOut, len(urls)-failed, failed)
+	fmt.Printf("Manifest: %s\n", manifestPath)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d URLs failed, see %s", failed, len(urls), manifestPath)
+	}
+	return nil
+}
+
+// snapshotURL renders one URL, retrying on failure, and writes its output files
+func snapshotURL(browser *client.Browser, pageURL string, opts operations.RenderOptions) snapshotEntry {
+	entry := snapshotEntry{URL: pageURL, StartedAt: time.Now()}
+	name := snapshotName(pageURL)
+
+	var lastErr error
+	for attempt := 0; attempt <= batchRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		entry.Attempts++
+
+		result, err := browser.RenderURL(pageURL, opts)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		entry.Title = result.Title
+		if opts.PDF != nil {
+			entry.PDF = name + ".pdf"
+			if err := os.WriteFile(filepath.Join(batchOut, entry.PDF), result.PDF, 0644); err != nil {
+				lastErr = fmt.Errorf("failed to write PDF: %w", err)
+				break
+			}
+		}
+		if opts.Screenshot != nil {
+			entry.PNG = name + ".png"
+			if err := os.WriteFile(filepath.Join(batchOut, entry.PNG), result.Screenshot, 0644); err != nil {
+				lastErr = fmt.Errorf("failed to write screenshot: %w", err)
+				break
+			}
+		}
+
+		lastErr = nil
+		break
+	}
+
+	entry.DurationMs = time.Since(entry.StartedAt).Milliseconds()
+	if lastErr != nil {
+		entry.Status = "error"
+		entry.Error = lastErr.Error()
+	} else {
+		entry.Status = "ok"
+	}
+	return entry
+}
+
+// unsafeNameChars matches characters not allowed in snapshot file names
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// snapshotName derives a stable, filesystem-safe file name (without extension) from a URL:
+// a readable host/path slug followed by a short hash of the full URL to avoid collisions
+func snapshotName(pageURL string) string {
+	slug := pageURL
+	if u, err := url.Parse(pageURL); err == nil && u.Host != "" {
+		slug = u.Host + u.Path
+	}
+	slug = strings.Trim(unsafeNameChars.ReplaceAllString(slug, "_"), "_.")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+
+	sum := sha1.Sum([]byte(pageURL))
+	return slug + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// readURLList reads non-empty, non-comment lines from a file or stdin ("-")
+func readURLList(path string) ([]string, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open URL list: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+	return urls, nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/matejch/brow/pkg/config"
+)
+
+// stateDir returns the directory where brow keeps state between commands.
+// State is kept per debugging port so separate Chrome instances don't share it.
+func stateDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "brow", fmt.Sprintf("port-%d", config.ResolvePort(Port))), nil
+}
+
+// statePath returns the path of a file in the state directory, creating the directory if needed
+func statePath(file string) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// loadState reads the named state file into v
+// A missing file is not an error; v is left untouched
+func loadState(name string, v interface{}) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s state: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s state: %w", name, err)
+	}
+	return nil
+}
+
+// saveState writes v as the named state file
+func saveState(name string, v interface{}) error {
+	path, err := statePath(name + ".json")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s state: %w", name, err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s state: %w", name, err)
+	}
+	return nil
+}
+
+// removeState deletes the named state file; a missing file is not an error
+func removeState(name string) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s state: %w", name, err)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
@@ -11,11 +14,16 @@ import (
 )
 
 var (
-	storageType  string
-	key          string
-	value        string
-	deleteKey    bool
-	clearStorage bool
+	storageType   string
+	key           string
+	value         string
+	deleteKey     bool
+	clearStorage  bool
+	storageOrigin string
+	listOrigins   bool
+	jsonValues    bool
+	importFile    string
+	exportFile    string
 )
 
 var storageCmd = &cobra.Command{
@@ -23,7 +31,26 @@ var storageCmd = &cobra.Command{
 	Short: "Interact with localStorage and sessionStorage",
 	Long: `Get, set, or clear browser storage (localStorage or sessionStorage).
 By default, retrieves all items from localStorage as JSON.
-Use --type to specify localStorage (default) or sessionStorage.`,
+Use --type to specify localStorage (default) or sessionStorage.
+
+Use --origin to work with another origin's storage through the DOMStorage domain without
+navigating the current tab. Origins that aren't loaded in the current tab are opened on a
+blank page in a temporary tab; their sessionStorage isn't reachable that way.
+Use --list-origins to list the origins loaded in open tabs with their item counts.
+
+With --json, values are parsed as JSON when read (values that aren't JSON stay strings)
+and --value must be JSON, which is stored compactly. --export writes all items as a JSON
+object; --import sets all items of a JSON object in one round-trip, storing strings as-is
+and other values JSON-encoded. Plain --export/--import round-trip values exactly.
+
+Subcommands manage service workers and Cache Storage, and clear selected types of
+origin data.`,
+	Example: `  brow storage --json
+  brow storage --key settings --value '{"theme":"dark"}' --json
+  brow storage --export backup.json && brow storage --import backup.json
+  brow storage --origin https://app.example.com
+  brow storage --origin https://app.example.com --key token --value abc
+  brow storage --list-origins`,
 	RunE: runStorage,
 }
 
@@ -34,10 +61,15 @@ func init() {
 	storageCmd.Flags().StringVarP(&value, "value", "v", "", "Value to set (requires --key)")
 	storageCmd.Flags().BoolVarP(&deleteKey, "delete", "d", false, "Delete the specified key")
 	storageCmd.Flags().BoolVarP(&clearStorage, "clear", "c", false, "Clear all storage")
+	storageCmd.PersistentFlags().StringVarP(&storageOrigin, "origin", "o", "", "Origin whose storage to use (default: current page)")
+	storageCmd.Flags().BoolVarP(&jsonValues, "json", "j", false, "Parse values as JSON on read; require JSON for --value")
+	storageCmd.Flags().StringVar(&importFile, "import", "", "Set all items from a JSON object file (- for stdin)")
+	storageCmd.Flags().StringVar(&exportFile, "export", "", "Write all items as a JSON object to a file (- for stdout)")
+	storageCmd.Flags().BoolVar(&listOrigins, "list-origins", false, "List origins loaded in open tabs and their storage item counts")
 }
 
 func runStorage(_ *cobra.Command, _ []string) error {
-	browser, err := client.New(&config.Config{
+	browser, err := newBrowser(&config.Config{
 		Port: config.ResolvePort(Port),
 	})
 	if err != nil {
@@ -47,6 +79,14 @@ func runStorage(_ *cobra.Command, _ []string) error {
 
 	page := browser.Page()
 
+	if listOrigins {
+		origins, err := browser.StorageOrigins()
+		if err != nil {
+			return err
+		}
+		return printJSON(origins)
+	}
+
 	// Determine storage type
 	var st operations.StorageType
 	var storageName string
@@ -58,6 +98,25 @@ func runStorage(_ *cobra.Command, _ []string) error {
 		storageName = "localStorage"
 	}
 
+	if jsonValues && key != "" && value != "" {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(value)); err != nil {
+			return fmt.Errorf("--value is not valid JSON: %w", err)
+		}
+		value = compact.String()
+	}
+
+	if importFile != "" {
+		return runStorageImport(browser, st, storageName)
+	}
+	if exportFile != "" {
+		return runStorageExport(browser, st)
+	}
+
+	if storageOrigin != "" {
+		return runOriginStorage(browser, st, storageName)
+	}
+
 	// Clear storage
 	if clearStorage {
 		if err := page.ClearStorage(st); err != nil {
@@ -91,11 +150,22 @@ func runStorage(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return err
 		}
+		if s, ok := result.(string); ok && jsonValues {
+			return printJSON(operations.DecodeStorageValue(s))
+		}
 		fmt.Printf("%v\n", result)
 		return nil
 	}
 
 	// Get all items (default)
+	if jsonValues {
+		items, err := page.ExportStorage(st)
+		if err != nil {
+			return err
+		}
+		return printJSON(operations.DecodeStorageValues(items))
+	}
+
 	result, err := page.GetAllStorage(st)
 	if err != nil {
 		return err
@@ -110,3 +180,135 @@ func runStorage(_ *cobra.Command, _ []string) error {
 	fmt.Println(string(output))
 	return nil
 }
+
+// runStorageImport sets all items of the --import JSON object
+func runStorageImport(browser *client.Browser, st operations.StorageType, storageName string) error {
+	var data []byte
+	var err error
+	if importFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(importFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", importFile, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var items map[string]interface{}
+	if err := decoder.Decode(&items); err != nil {
+		return fmt.Errorf("failed to parse %s (expected a JSON object): %w", importFile, err)
+	}
+
+	if storageOrigin != "" {
+		encoded, err := operations.EncodeStorageValues(items)
+		if err != nil {
+			return err
+		}
+		err = browser.SetOriginStorageItems(storageOrigin, st, encoded)
+	} else {
+		err = browser.Page().ImportStorage(st, items)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d items into %s\n", len(items), storageName)
+	return nil
+}
+
+// runStorageExport writes all items to --export as a JSON object
+func runStorageExport(browser *client.Browser, st operations.StorageType) error {
+	var items map[string]string
+	var err error
+	if storageOrigin != "" {
+		items, err = browser.OriginStorage(storageOrigin, st)
+	} else {
+		items, err = browser.Page().ExportStorage(st)
+	}
+	if err != nil {
+		return err
+	}
+
+	var v interface{} = items
+	if jsonValues {
+		v = operations.DecodeStorageValues(items)
+	}
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format storage: %w", err)
+	}
+	output = append(output, '\n')
+
+	if exportFile == "-" {
+		_, err = os.Stdout.Write(output)
+		return err
+	}
+	if err := os.WriteFile(exportFile, output, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportFile, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d items to %s\n", len(items), exportFile)
+	return nil
+}
+
+// runOriginStorage runs the storage operation against --origin instead of the current page
+func runOriginStorage(browser *client.Browser, st operations.StorageType, storageName string) error {
+	switch {
+	case clearStorage:
+		if err := browser.ClearOriginStorage(storageOrigin, st); err != nil {
+			return err
+		}
+		fmt.Printf("%s of %s cleared\n", storageName, storageOrigin)
+		return nil
+
+	case deleteKey && key != "":
+		if err := browser.RemoveOriginStorageItem(storageOrigin, st, key); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted key: %s\n", key)
+		return nil
+
+	case key != "" && value != "":
+		if err := browser.SetOriginStorageItems(storageOrigin, st, map[string]string{key: value}); err != nil {
+			return err
+		}
+		fmt.Printf("Set %s[%s] = %s\n", storageName, key, value)
+		return nil
+	}
+
+	items, err := browser.OriginStorage(storageOrigin, st)
+	if err != nil {
+		return err
+	}
+
+	if key != "" {
+		item, ok := items[key]
+		if !ok {
+			fmt.Println("<nil>")
+			return nil
+		}
+		if jsonValues {
+			return printJSON(operations.DecodeStorageValue(item))
+		}
+		fmt.Println(item)
+		return nil
+	}
+
+	if jsonValues {
+		return printJSON(operations.DecodeStorageValues(items))
+	}
+	return printJSON(items)
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format storage: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
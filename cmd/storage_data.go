@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unregisterScope string
+	cacheFilter     string
+	cacheDelete     bool
+	clearDataTypes  []string
+)
+
+var storageSWCmd = &cobra.Command{
+	Use:     "service-workers",
+	Aliases: []string{"sw"},
+	Short:   "List or unregister service workers",
+	Long: `Lists service worker registrations of all origins with their versions, script URLs
+and status. Use --unregister with a scope URL to remove a registration, for example when
+a stale worker keeps serving old assets.`,
+	Example: `  brow storage sw
+  brow storage sw --unregister https://app.example.com/`,
+	Args: cobra.NoArgs,
+	RunE: runStorageSW,
+}
+
+var storageCachesCmd = &cobra.Command{
+	Use:   "caches [cache [url]]",
+	Short: "Inspect and delete Cache Storage",
+	Long: `Without arguments, lists the Cache Storage caches of the origin with entry counts.
+With a cache name, lists its entries (URL, status, type, time and response headers).
+With a cache name and URL, writes the cached response body to stdout.
+
+--delete deletes the cache, or only the entry when a URL is given.`,
+	Example: `  brow storage caches
+  brow storage caches assets-v3 --filter /js/
+  brow storage caches assets-v3 https://app.example.com/app.js > app.js
+  brow storage caches assets-v3 --delete`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runStorageCaches,
+}
+
+var storageClearDataCmd = &cobra.Command{
+	Use:   "clear-data",
+	Short: "Clear selected types of stored data for an origin",
+	Long: `Clears stored data for the origin (--origin, or the current page's) with
+Storage.clearDataForOrigin. --types selects what to clear; the default is all.
+
+Types: ` + strings.Join(operations.OriginDataTypes, ", "),
+	Example: `  brow storage clear-data --types service_workers,cache_storage
+  brow storage clear-data --origin https://app.example.com`,
+	Args: cobra.NoArgs,
+	RunE: runStorageClearData,
+}
+
+func init() {
+	storageCmd.AddCommand(storageSWCmd, storageCachesCmd, storageClearDataCmd)
+	storageSWCmd.Flags().StringVar(&unregisterScope, "unregister", "", "Unregister the service worker with this scope URL")
+	storageCachesCmd.Flags().StringVar(&cacheFilter, "filter", "", "Only list entries whose path contains this string")
+	storageCachesCmd.Flags().BoolVar(&cacheDelete, "delete", false, "Delete the cache, or the entry if a URL is given")
+	storageClearDataCmd.Flags().StringSliceVar(&clearDataTypes, "types", nil, "Comma-separated storage types to clear (default: all)")
+}
+
+func runStorageSW(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	page := browser.Page()
+
+	if unregisterScope != "" {
+		if err := page.UnregisterServiceWorker(unregisterScope); err != nil {
+			return err
+		}
+		fmt.Printf("Unregistered service worker: %s\n", unregisterScope)
+		return nil
+	}
+
+	workers, err := page.ServiceWorkers()
+	if err != nil {
+		return err
+	}
+	return printJSON(workers)
+}
+
+func runStorageCaches(_ *cobra.Command, args []string) error {
+	if cacheDelete && len(args) == 0 {
+		return fmt.Errorf("--delete requires a cache name")
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	page := browser.Page()
+
+	switch {
+	case cacheDelete:
+		requestURL := ""
+		if len(args) == 2 {
+			requestURL = args[1]
+		}
+		if err := page.DeleteCache(storageOrigin, args[0], requestURL); err != nil {
+			return err
+		}
+		if requestURL != "" {
+			fmt.Printf("Deleted %s from cache %s\n", requestURL, args[0])
+		} else {
+			fmt.Printf("Deleted cache: %s\n", args[0])
+		}
+		return nil
+
+	case len(args) == 2:
+		body, err := page.CachedResponseBody(storageOrigin, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(body)
+		return err
+
+	case len(args) == 1:
+		entries, err := page.CacheEntries(storageOrigin, args[0], cacheFilter)
+		if err != nil {
+			return err
+		}
+		return printJSON(entries)
+	}
+
+	caches, err := page.Caches(storageOrigin)
+	if err != nil {
+		return err
+	}
+	return printJSON(caches)
+}
+
+func runStorageClearData(_ *cobra.Command, _ []string) error {
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if err := browser.Page().ClearOriginData(storageOrigin, clearDataTypes...); err != nil {
+		return err
+	}
+
+	types := "all"
+	if len(clearDataTypes) > 0 {
+		types = strings.Join(clearDataTypes, ", ")
+	}
+	target := storageOrigin
+	if target == "" {
+		target = "current page"
+	}
+	fmt.Printf("Cleared %s data for %s\n", types, target)
+	return nil
+}
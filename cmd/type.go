@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var typeCmd = &cobra.Command{
+	Use:   "type <selector> <text>",
+	Short: "Type text into an element",
+	Long: `Focuses the element matching a CSS selector and types the given text.
+Accepts "#12"-style references to elements from the last 'brow screenshot --annotate'.
+Selectors may step into iframes (including cross-site ones) and open shadow roots with
+" >>> ", as printed by 'brow pick': 'iframe#login >>> my-form >>> button[type=submit]'.
+Use --frame to resolve the selector inside an iframe (see 'brow frames').`,
+	Args: cobra.ExactArgs(2),
+	RunE: runType,
+}
+
+func init() {
+	rootCmd.AddCommand(typeCmd)
+	addFrameFlag(typeCmd)
+}
+
+func runType(_ *cobra.Command, args []string) error {
+	selector, err := resolveSelector(args[0])
+	if err != nil {
+		return err
+	}
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if frameSpec != "" {
+		err = browser.Page().TypeInFrame(frameSpec, selector, args[1])
+	} else {
+		err = browser.Page().Type(selector, args[1])
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Typed into: %s\n", selector)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/matejch/brow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload <selector> <file>...",
+	Short: "Select files in a file input",
+	Long: `Selects files in the file input matching a CSS selector, as if the user had picked them.
+The input may be hidden, as it often is behind a styled button.
+
+If the selector matches a button, label or other element that opens a file chooser when
+clicked, brow clicks it and hands the files to the chooser instead of showing it.
+
+Accepts "#12"-style references to elements from the last 'brow screenshot --annotate'.
+Selectors may step into iframes and open shadow roots with " >>> ", as printed by
+'brow pick'. Use --frame to resolve the selector inside an iframe (see 'brow frames').`,
+	Example: `  brow upload 'input[type=file]' report.pdf
+  brow upload '#photos' a.jpg b.jpg
+  brow upload 'button.attach' notes.txt`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runUpload,
+}
+
+func init() {
+	rootCmd.AddCommand(uploadCmd)
+	addFrameFlag(uploadCmd)
+}
+
+func runUpload(_ *cobra.Command, args []string) error {
+	selector, err := resolveSelector(args[0])
+	if err != nil {
+		return err
+	}
+	files := args[1:]
+
+	browser, err := newBrowser(&config.Config{
+		Port: config.ResolvePort(Port),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	if frameSpec != "" {
+		err = browser.Page().SetInputFilesInFrame(frameSpec, selector, files)
+	} else {
+		err = browser.Page().SetInputFiles(selector, files)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Uploaded %d file(s) to: %s\n", len(files), selector)
+	return nil
+}
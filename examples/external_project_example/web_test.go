@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/matejch/brow/pkg/client"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/imagediff"
 	"github.com/matejch/brow/pkg/operations"
 )
 
@@ -94,3 +98,53 @@ func TestLoginFlow(t *testing.T) {
 
 	t.Logf("Found %d cookies after navigation", len(cookies))
 }
+
+// Example: Visual regression test against a stored baseline
+// Delete testdata/homepage.png to record a new baseline
+func TestVisualRegression(t *testing.T) {
+	browser, err := client.New(nil)
+	if err != nil {
+		t.Skip("Chrome not running")
+	}
+	defer browser.Close()
+
+	page := browser.Page()
+
+	if _, err := page.Navigate("https://example.com", true); err != nil {
+		t.Fatal(err)
+	}
+
+	screenshot, err := page.Screenshot(operations.ScreenshotOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baselinePath := filepath.Join("testdata", "homepage.png")
+	baseline, err := os.ReadFile(baselinePath)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(baselinePath, screenshot, 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Skipf("Recorded new baseline at %s", baselinePath)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := imagediff.CompareBytes(baseline, screenshot, imagediff.Options{
+		Threshold:    0.1,
+		MaxDiffRatio: 0.001,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Match {
+		diff, _ := result.EncodePNG()
+		_ = os.WriteFile(filepath.Join("testdata", "homepage.diff.png"), diff, 0644)
+		t.Errorf("Page differs from baseline: %d pixels (%.2f%%)", result.DiffPixels, result.DiffRatio*100)
+	}
+}
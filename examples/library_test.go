@@ -158,6 +158,27 @@ func TestLibraryUsageCookies(t *testing.T) {
 	if !found {
 		t.Error("test_cookie was not found after setting")
 	}
+
+	// HttpOnly cookies can't be set through document.cookie, but work with structured params
+	err = page.SetCookieWithParams(operations.CookieParams{
+		Name:     "session_id",
+		Value:    "secret",
+		Domain:   "example.com",
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Lax",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := page.DeleteCookie("session_id", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected to delete 1 cookie, deleted %d", deleted)
+	}
 }
 
 // TestLibraryUsageStorage demonstrates localStorage/sessionStorage
@@ -538,3 +559,72 @@ func TestCloseTab(t *testing.T) {
 
 	t.Logf("CloseTab working correctly")
 }
+
+// TestAnnotatedScreenshot demonstrates set-of-marks screenshots for vision agents
+func TestAnnotatedScreenshot(t *testing.T) {
+	browser, err := client.New(nil)
+	if err != nil {
+		t.Skip("Skipping test: Chrome not running")
+	}
+	defer browser.Close()
+
+	page := browser.Page()
+
+	_, err = page.Navigate("https://example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screenshot, marks, err := page.AnnotatedScreenshot(operations.ScreenshotOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(screenshot) == 0 {
+		t.Error("screenshot data is empty")
+	}
+
+	// example.com has a single "More information..." link
+	if len(marks) == 0 {
+		t.Fatal("expected at least one labelled element")
+	}
+	if marks[0].Index != 1 || marks[0].Role != "link" {
+		t.Errorf("expected mark #1 to be a link, got %+v", marks[0])
+	}
+
+	t.Logf("Annotated %d elements, first: %s (%q)", len(marks), marks[0].Selector, marks[0].Text)
+}
+
+// TestRenderHTML demonstrates rendering generated HTML to PDF without a web server
+func TestRenderHTML(t *testing.T) {
+	browser, err := client.New(nil)
+	if err != nil {
+		t.Skip("Skipping test: Chrome not running")
+	}
+	defer browser.Close()
+
+	initialCount := browser.TabCount()
+
+	result, err := browser.RenderHTML(`<html><head><title>Report</title></head><body><h1>Q3 Report</h1></body></html>`,
+		operations.RenderOptions{
+			PDF:        &operations.PDFOptions{Paper: operations.PaperA4, PrintBackground: true},
+			Screenshot: &operations.ScreenshotOptions{},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Title != "Report" {
+		t.Errorf("expected title 'Report', got %q", result.Title)
+	}
+	if len(result.PDF) == 0 || len(result.Screenshot) == 0 {
+		t.Error("expected both PDF and screenshot output")
+	}
+
+	// The temporary tab is not tracked by the browser
+	if browser.TabCount() != initialCount {
+		t.Errorf("expected %d tabs after rendering, got %d", initialCount, browser.TabCount())
+	}
+
+	t.Logf("Rendered PDF: %d bytes, screenshot: %d bytes", len(result.PDF), len(result.Screenshot))
+}
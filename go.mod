@@ -6,6 +6,8 @@ require (
 	github.com/chromedp/cdproto v0.0.0-20250724212937-08a3db8b4327
 	github.com/chromedp/chromedp v0.14.2
 	github.com/spf13/cobra v1.10.1
+	golang.org/x/net v0.42.0
+	golang.org/x/term v0.33.0
 )
 
 require (
@@ -18,4 +20,3 @@ require (
 	github.com/spf13/pflag v1.0.9 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 )
-
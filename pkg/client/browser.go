@@ -2,13 +2,17 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
 	"github.com/matejch/brow/pkg/config"
+	"github.com/matejch/brow/pkg/operations"
 )
 
 const (
@@ -244,6 +248,98 @@ func (b *Browser) CloseTab(index int) error {
 	return nil
 }
 
+// HandleDialogs answers JavaScript dialogs opened in the tabs according to policy and reports
+// each dialog to onDialog (which may be nil). Call it right after New, before using the tabs,
+// so dialogs opened while brow attaches are caught; tabs opened later are not covered.
+func (b *Browser) HandleDialogs(policy operations.DialogPolicy, onDialog func(operations.Dialog)) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, tab := range b.tabs {
+		operations.HandleDialogs(tab.ctx, policy, onDialog)
+	}
+}
+
+// AnswerPendingDialogs answers dialogs that were already open when connecting and returns them
+// An open dialog blocks its page, so commands on that tab would otherwise wait for it.
+func (b *Browser) AnswerPendingDialogs(policy operations.DialogPolicy) ([]operations.Dialog, error) {
+	b.mu.RLock()
+	targets := make([]target.ID, 0, len(b.tabs))
+	for _, tab := range b.tabs {
+		if tab.targetID != "" {
+			targets = append(targets, tab.targetID)
+		}
+	}
+	b.mu.RUnlock()
+
+	var dialogs []operations.Dialog
+	var errs []error
+	for _, id := range targets {
+		wsURL := fmt.Sprintf("ws://%s:%d/devtools/page/%s", defaultHost, b.config.Port, id)
+		dialog, err := operations.AnswerPendingDialog(b.allocCtx, wsURL, policy)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tab %s: %w", id, err))
+			continue
+		}
+		if dialog != nil {
+			dialogs = append(dialogs, *dialog)
+		}
+	}
+	return dialogs, errors.Join(errs...)
+}
+
+// RenderHTML renders an HTML document in a fresh tab and returns a PDF and/or screenshot
+// The tab is closed afterwards, leaving existing tabs untouched
+func (b *Browser) RenderHTML(html string, opts operations.RenderOptions) (*operations.RenderResult, error) {
+	var result *operations.RenderResult
+	err := b.withTemporaryTab(func(ctx context.Context) error {
+		var err error
+		result, err = operations.RenderHTML(ctx, html, opts)
+		return err
+	})
+	return result, err
+}
+
+// RenderFile renders a local HTML file in a fresh tab, like RenderHTML
+// Loading it through a file:// URL lets relative images, stylesheets and fonts resolve
+func (b *Browser) RenderFile(path string, opts operations.RenderOptions) (*operations.RenderResult, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String()
+
+	return b.RenderURL(fileURL, opts)
+}
+
+// RenderURL loads a URL in a fresh tab, like RenderHTML
+// It is safe to call concurrently; each call uses its own tab
+func (b *Browser) RenderURL(pageURL string, opts operations.RenderOptions) (*operations.RenderResult, error) {
+	var result *operations.RenderResult
+	err := b.withTemporaryTab(func(ctx context.Context) error {
+		var err error
+		result, err = operations.RenderURL(ctx, pageURL, opts)
+		return err
+	})
+	return result, err
+}
+
+// withTemporaryTab runs fn in a new tab that is closed when fn returns
+func (b *Browser) withTemporaryTab(fn func(ctx context.Context) error) error {
+	tabCtx, tabCancel := chromedp.NewContext(b.allocCtx)
+	// Cancelling the tab context closes the tab
+	defer tabCancel()
+
+	ctx := tabCtx
+	if b.config.Timeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(tabCtx, b.config.Timeout)
+		defer timeoutCancel()
+	}
+
+	return fn(ctx)
+}
+
 // Context returns the underlying context for the first tab (backward compatible)
 func (b *Browser) Context() context.Context {
 	b.mu.RLock()
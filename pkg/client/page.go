@@ -24,26 +24,140 @@ func (p *Page) Eval(script string) (interface{}, error) {
 	return operations.Evaluate(p.ctx, script)
 }
 
+// EvalIsolated executes JavaScript in an isolated world that shares the DOM but not the page's globals
+func (p *Page) EvalIsolated(script string) (interface{}, error) {
+	return operations.EvaluateIsolated(p.ctx, script)
+}
+
+// EvalInto executes JavaScript, awaits a returned promise and decodes the result into dst
+func (p *Page) EvalInto(script string, dst interface{}) error {
+	return operations.EvaluateInto(p.ctx, script, dst)
+}
+
+// EvalWithOptions executes JavaScript with arguments, promise awaiting, a timeout or in a frame
+func (p *Page) EvalWithOptions(script string, opts operations.EvalOptions) (interface{}, error) {
+	return operations.EvaluateWithOptions(p.ctx, script, opts)
+}
+
+// AddInitScript registers JavaScript to run in every new document before the page's scripts
+// Scripts stay registered until RemoveInitScript or until the browser connection is closed
+func (p *Page) AddInitScript(script string) (string, error) {
+	return operations.AddInitScript(p.ctx, script)
+}
+
+// RemoveInitScript unregisters a script added with AddInitScript
+func (p *Page) RemoveInitScript(id string) error {
+	return operations.RemoveInitScript(p.ctx, id)
+}
+
+// CheckSyntax compiles JavaScript without running it and returns its syntax error, if any
+func (p *Page) CheckSyntax(script string) error {
+	return operations.CheckSyntax(p.ctx, script)
+}
+
+// InterruptScript terminates JavaScript currently running in the page
+func (p *Page) InterruptScript() error {
+	return operations.InterruptScript(p.ctx)
+}
+
+// HandleDialog answers the JavaScript dialog currently open in the page
+// promptText is the answer to a prompt() dialog when accepting it
+func (p *Page) HandleDialog(accept bool, promptText string) error {
+	return operations.HandleDialog(p.ctx, accept, promptText)
+}
+
 // Screenshot captures a screenshot of the current page
 func (p *Page) Screenshot(opts operations.ScreenshotOptions) ([]byte, error) {
 	return operations.CaptureScreenshot(p.ctx, opts)
 }
 
+// AnnotatedScreenshot captures a screenshot with numbered labels over interactive elements
+// and returns the legend describing each label
+func (p *Page) AnnotatedScreenshot(opts operations.ScreenshotOptions) ([]byte, []operations.Mark, error) {
+	return operations.AnnotatedScreenshot(p.ctx, opts)
+}
+
+// ElementRegions returns the screenshot-pixel bounding boxes of elements matching the selectors
+func (p *Page) ElementRegions(selectors []string, fullPage bool) ([]operations.BoundingBox, error) {
+	return operations.ElementRegions(p.ctx, selectors, fullPage)
+}
+
+// Click clicks the element matching the CSS selector
+func (p *Page) Click(selector string) error {
+	return operations.Click(p.ctx, selector)
+}
+
+// Type types text into the element matching the CSS selector
+func (p *Page) Type(selector, text string) error {
+	return operations.Type(p.ctx, selector, text)
+}
+
+// SetInputFiles selects files in the file input matching the selector, or in the file chooser
+// opened by clicking the matched element
+func (p *Page) SetInputFiles(selector string, files []string) error {
+	return operations.SetInputFiles(p.ctx, selector, files)
+}
+
+// Frames returns the page's frame tree, including out-of-process iframes
+func (p *Page) Frames() ([]operations.Frame, error) {
+	return operations.ListFrames(p.ctx)
+}
+
+// EvalInFrame executes JavaScript in a frame chosen by name, URL substring or iframe selector
+func (p *Page) EvalInFrame(frame, script string) (interface{}, error) {
+	return operations.EvaluateInFrame(p.ctx, frame, script)
+}
+
+// ClickInFrame clicks the element matching the selector inside a frame
+func (p *Page) ClickInFrame(frame, selector string) error {
+	return operations.ClickInFrame(p.ctx, frame, selector)
+}
+
+// TypeInFrame types text into the element matching the selector inside a frame
+func (p *Page) TypeInFrame(frame, selector, text string) error {
+	return operations.TypeInFrame(p.ctx, frame, selector, text)
+}
+
+// SetInputFilesInFrame selects files like SetInputFiles for an element inside a frame
+func (p *Page) SetInputFilesInFrame(frame, selector string, files []string) error {
+	return operations.SetInputFilesInFrame(p.ctx, frame, selector, files)
+}
+
+// StartScreencast starts recording frames of the page; call Stop on the result to finish
+func (p *Page) StartScreencast(opts operations.ScreencastOptions) (*operations.Screencast, error) {
+	return operations.StartScreencast(p.ctx, opts)
+}
+
 // PDF generates a PDF from the current page
 func (p *Page) PDF(opts operations.PDFOptions) ([]byte, error) {
 	return operations.GeneratePDF(p.ctx, opts)
 }
 
-// GetCookies retrieves all cookies, optionally filtered by domain
+// GetCookies retrieves all cookies, optionally filtered by domain (including subdomains)
 func (p *Page) GetCookies(domain string) ([]*network.Cookie, error) {
 	return operations.GetCookies(p.ctx, domain)
 }
 
-// SetCookie sets a cookie
+// ImportCookies sets cookies previously returned by GetCookies or read with operations.ParseCookies
+func (p *Page) ImportCookies(cookies []*network.Cookie) error {
+	return operations.ImportCookies(p.ctx, cookies)
+}
+
+// SetCookie sets a cookie from a Set-Cookie style string ("name=value; Domain=.example.com; HttpOnly")
 func (p *Page) SetCookie(cookie string) error {
 	return operations.SetCookie(p.ctx, cookie)
 }
 
+// SetCookieWithParams sets a cookie with explicit attributes, including HttpOnly and partitioned cookies
+func (p *Page) SetCookieWithParams(params operations.CookieParams) error {
+	return operations.SetCookieWithParams(p.ctx, params)
+}
+
+// DeleteCookie deletes cookies with the given name, for one domain or all domains if domain is empty
+func (p *Page) DeleteCookie(name, domain string) (int, error) {
+	return operations.DeleteCookie(p.ctx, name, domain)
+}
+
 // ClearCookies clears all browser cookies
 func (p *Page) ClearCookies() error {
 	return operations.ClearCookies(p.ctx)
@@ -69,6 +183,26 @@ func (p *Page) RemoveStorageItem(storageType operations.StorageType, key string)
 	return operations.RemoveStorageItem(p.ctx, storageType, key)
 }
 
+// GetStorageJSON reads a JSON-encoded item from storage into v
+func (p *Page) GetStorageJSON(storageType operations.StorageType, key string, v interface{}) error {
+	return operations.GetStorageJSON(p.ctx, storageType, key, v)
+}
+
+// SetStorageJSON stores v JSON-encoded
+func (p *Page) SetStorageJSON(storageType operations.StorageType, key string, v interface{}) error {
+	return operations.SetStorageJSON(p.ctx, storageType, key, v)
+}
+
+// ExportStorage retrieves all items from storage as strings
+func (p *Page) ExportStorage(storageType operations.StorageType) (map[string]string, error) {
+	return operations.ExportStorage(p.ctx, storageType)
+}
+
+// ImportStorage sets all items in one round-trip; non-string values are stored as JSON
+func (p *Page) ImportStorage(storageType operations.StorageType, items map[string]interface{}) error {
+	return operations.ImportStorage(p.ctx, storageType, items)
+}
+
 // ClearStorage clears all items from the specified storage
 func (p *Page) ClearStorage(storageType operations.StorageType) error {
 	return operations.ClearStorage(p.ctx, storageType)
@@ -79,6 +213,23 @@ func (p *Page) InjectPicker(useXPath bool) error {
 	return operations.InjectPicker(p.ctx, useXPath)
 }
 
+// Pick shows the element picker and blocks until the user clicks an element, presses ESC
+// (operations.ErrPickCancelled) or the timeout expires (operations.ErrPickTimeout)
+func (p *Page) Pick(opts operations.PickOptions) (*operations.PickedElement, error) {
+	return operations.PickElement(p.ctx, opts)
+}
+
+// PickElements shows the picker in multiple mode and blocks until the user presses Enter,
+// returning the picked elements and a generalized selector matching all of them
+func (p *Page) PickElements(opts operations.PickOptions) (*operations.PickedGroup, error) {
+	return operations.PickElements(p.ctx, opts)
+}
+
+// InjectPickerWithOptions injects the picker with multiple or similar mode without waiting
+func (p *Page) InjectPickerWithOptions(opts operations.PickOptions) error {
+	return operations.InjectPickerWithOptions(p.ctx, opts)
+}
+
 // GetPickedSelector retrieves the selector picked by the user
 func (p *Page) GetPickedSelector() (string, error) {
 	return operations.GetPickedSelector(p.ctx)
@@ -88,3 +239,65 @@ func (p *Page) GetPickedSelector() (string, error) {
 func (p *Page) Context() context.Context {
 	return p.ctx
 }
+
+// WithContext returns a copy of the page whose operations use ctx, which must be derived
+// from Context(); cancelling it aborts the operations without closing the tab
+func (p *Page) WithContext(ctx context.Context) *Page {
+	return &Page{ctx: ctx, config: p.config}
+}
+
+// StorageOrigins lists the origins of all frames in this tab with their storage item counts
+func (p *Page) StorageOrigins() ([]operations.StorageOrigin, error) {
+	return operations.StorageOrigins(p.ctx)
+}
+
+// ListIndexedDB lists IndexedDB databases and object stores of an origin loaded in this tab
+// An empty origin uses the current page's origin
+func (p *Page) ListIndexedDB(origin string) ([]operations.IDBDatabase, error) {
+	return operations.ListIndexedDB(p.ctx, origin)
+}
+
+// DumpIndexedDB reads up to limit records (0 for all) of an object store, calling fn for each
+func (p *Page) DumpIndexedDB(origin, database, store string, limit int, fn func(operations.IDBRecord) error) error {
+	return operations.DumpIndexedDB(p.ctx, origin, database, store, limit, fn)
+}
+
+// ClearIndexedDB empties an object store, deletes a database, or deletes all databases of an origin
+func (p *Page) ClearIndexedDB(origin, database, store string) error {
+	return operations.ClearIndexedDB(p.ctx, origin, database, store)
+}
+
+// ServiceWorkers lists the service worker registrations of all origins
+func (p *Page) ServiceWorkers() ([]operations.ServiceWorker, error) {
+	return operations.ListServiceWorkers(p.ctx)
+}
+
+// UnregisterServiceWorker unregisters the service worker registration with the given scope URL
+func (p *Page) UnregisterServiceWorker(scope string) error {
+	return operations.UnregisterServiceWorker(p.ctx, scope)
+}
+
+// Caches lists the Cache Storage caches of an origin ("" for the current page's origin)
+func (p *Page) Caches(origin string) ([]operations.CacheInfo, error) {
+	return operations.ListCaches(p.ctx, origin)
+}
+
+// CacheEntries lists the entries of a cache, optionally filtered by path
+func (p *Page) CacheEntries(origin, cacheName, pathFilter string) ([]operations.CacheEntry, error) {
+	return operations.CacheEntries(p.ctx, origin, cacheName, pathFilter)
+}
+
+// CachedResponseBody returns the body of the response cached for requestURL
+func (p *Page) CachedResponseBody(origin, cacheName, requestURL string) ([]byte, error) {
+	return operations.CachedResponseBody(p.ctx, origin, cacheName, requestURL)
+}
+
+// DeleteCache deletes a cache, or only the entry for requestURL if it is set
+func (p *Page) DeleteCache(origin, cacheName, requestURL string) error {
+	return operations.DeleteCache(p.ctx, origin, cacheName, requestURL)
+}
+
+// ClearOriginData clears the selected storage types (see operations.OriginDataTypes) of an origin
+func (p *Page) ClearOriginData(origin string, types ...string) error {
+	return operations.ClearOriginData(p.ctx, origin, types)
+}
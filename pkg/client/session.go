@@ -0,0 +1,140 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/matejch/brow/pkg/operations"
+)
+
+// SaveSession captures cookies for all domains plus web storage for the given origins
+// With no origins, the current page's origin is used. The current origin is read from the
+// current tab, including its sessionStorage; for other origins only localStorage is
+// available, read without navigating the current tab (see OriginStorage).
+func (b *Browser) SaveSession(origins []string) (*operations.Session, error) {
+	page := b.Page()
+	if page == nil {
+		return nil, fmt.Errorf("no tab available")
+	}
+
+	cookies, err := page.GetCookies("")
+	if err != nil {
+		return nil, err
+	}
+	session := &operations.Session{Cookies: cookies}
+
+	current, currentErr := operations.CaptureOriginStorage(page.ctx, true)
+	if len(origins) == 0 {
+		if currentErr != nil {
+			return nil, fmt.Errorf("no origins given and %w", currentErr)
+		}
+		session.Origins = append(session.Origins, *current)
+		return session, nil
+	}
+
+	for _, rawOrigin := range origins {
+		origin, err := operations.NormalizeOrigin(rawOrigin)
+		if err != nil {
+			return nil, err
+		}
+
+		if currentErr == nil && current.Origin == origin {
+			session.Origins = append(session.Origins, *current)
+			continue
+		}
+
+		local, err := b.OriginStorage(origin, operations.LocalStorage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture storage for %s: %w", origin, err)
+		}
+		storage := &operations.OriginStorage{Origin: origin, LocalStorage: local}
+		session.Origins = append(session.Origins, *storage)
+	}
+
+	return session, nil
+}
+
+// LoadSession restores cookies and web storage saved by SaveSession
+// Each origin with storage is opened in the current tab as a blank page (see OpenOrigin), so
+// sessionStorage lands in the tab while redirects and the site's scripts are avoided;
+// afterwards the tab returns to the page it was on, if that was a web page.
+func (b *Browser) LoadSession(session *operations.Session) error {
+	page := b.Page()
+	if page == nil {
+		return fmt.Errorf("no tab available")
+	}
+
+	if len(session.Cookies) > 0 {
+		if err := page.ImportCookies(session.Cookies); err != nil {
+			return err
+		}
+	}
+
+	if len(session.Origins) == 0 {
+		return nil
+	}
+
+	location, err := page.Eval("location.href")
+	if err != nil {
+		return err
+	}
+
+	for _, storage := range session.Origins {
+		if len(storage.LocalStorage) == 0 && len(storage.SessionStorage) == 0 {
+			continue
+		}
+		if err := operations.OpenOrigin(page.ctx, storage.Origin); err != nil {
+			return err
+		}
+		if err := operations.RestoreOriginStorage(page.ctx, storage); err != nil {
+			return fmt.Errorf("failed to restore storage for %s: %w", storage.Origin, err)
+		}
+	}
+
+	if previous, ok := location.(string); ok {
+		if _, err := operations.NormalizeOrigin(previous); err == nil {
+			if _, err := page.Navigate(previous, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SaveSessionFile captures a session like SaveSession and writes it to path as JSON
+func (b *Browser) SaveSessionFile(path string, origins []string) (*operations.Session, error) {
+	session, err := b.SaveSession(origins)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format session: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return session, nil
+}
+
+// LoadSessionFile reads a session saved by SaveSessionFile and restores it with LoadSession
+func (b *Browser) LoadSessionFile(path string) (*operations.Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session operations.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+
+	if err := b.LoadSession(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/matejch/brow/pkg/operations"
+)
+
+// StorageOrigins lists the origins loaded in any open tab with their storage item counts
+// Chrome offers no way to enumerate every origin that has stored data, so only origins
+// with a frame in an open tab are listed. sessionStorage counts are summed across tabs.
+func (b *Browser) StorageOrigins() ([]operations.StorageOrigin, error) {
+	b.mu.RLock()
+	tabs := make([]context.Context, len(b.tabs))
+	for i, tab := range b.tabs {
+		tabs[i] = tab.ctx
+	}
+	b.mu.RUnlock()
+
+	var result []operations.StorageOrigin
+	index := make(map[string]int)
+	for _, ctx := range tabs {
+		origins, err := operations.StorageOrigins(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range origins {
+			if i, ok := index[o.Origin]; ok {
+				result[i].SessionItems += o.SessionItems
+				continue
+			}
+			index[o.Origin] = len(result)
+			result = append(result, o)
+		}
+	}
+
+	return result, nil
+}
+
+// OriginStorage retrieves all items of an origin's storage without navigating the current tab
+// If the origin isn't loaded in the current tab, its localStorage is read from a temporary
+// tab opened on a blank document of that origin. sessionStorage belongs to a tab, so it can
+// only be read for origins loaded in the current tab.
+func (b *Browser) OriginStorage(origin string, storageType operations.StorageType) (map[string]string, error) {
+	var items map[string]string
+	err := b.withOrigin(origin, storageType, func(ctx context.Context, origin string) error {
+		var err error
+		items, err = operations.GetOriginStorage(ctx, origin, storageType)
+		return err
+	})
+	return items, err
+}
+
+// SetOriginStorageItems sets values in an origin's storage, like OriginStorage
+func (b *Browser) SetOriginStorageItems(origin string, storageType operations.StorageType, items map[string]string) error {
+	return b.withOrigin(origin, storageType, func(ctx context.Context, origin string) error {
+		return operations.SetOriginStorageItems(ctx, origin, storageType, items)
+	})
+}
+
+// RemoveOriginStorageItem removes an item from an origin's storage, like OriginStorage
+func (b *Browser) RemoveOriginStorageItem(origin string, storageType operations.StorageType, key string) error {
+	return b.withOrigin(origin, storageType, func(ctx context.Context, origin string) error {
+		return operations.RemoveOriginStorageItem(ctx, origin, storageType, key)
+	})
+}
+
+// ClearOriginStorage clears an origin's storage, like OriginStorage
+func (b *Browser) ClearOriginStorage(origin string, storageType operations.StorageType) error {
+	return b.withOrigin(origin, storageType, func(ctx context.Context, origin string) error {
+		return operations.ClearOriginStorage(ctx, origin, storageType)
+	})
+}
+
+// withOrigin runs fn in the current tab if it has origin loaded, otherwise in a temporary tab
+// opened on origin (localStorage only)
+func (b *Browser) withOrigin(rawOrigin string, storageType operations.StorageType, fn func(ctx context.Context, origin string) error) error {
+	origin, err := operations.NormalizeOrigin(rawOrigin)
+	if err != nil {
+		return err
+	}
+
+	page := b.Page()
+	if page == nil {
+		return fmt.Errorf("no tab available")
+	}
+
+	err = fn(page.ctx, origin)
+	if !errors.Is(err, operations.ErrOriginNotLoaded) {
+		return err
+	}
+	if storageType == operations.SessionStorage {
+		return fmt.Errorf("sessionStorage of %s is only available while it is loaded in the current tab", origin)
+	}
+
+	return b.withTemporaryTab(func(ctx context.Context) error {
+		if err := operations.OpenOrigin(ctx, origin); err != nil {
+			return err
+		}
+		return fn(ctx, origin)
+	})
+}
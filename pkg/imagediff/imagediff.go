@@ -0,0 +1,175 @@
+// Package imagediff compares screenshots pixel by pixel for visual regression testing.
+// It is pure Go and has no dependency on the browser.
+package imagediff
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // Register JPEG decoder for full-page screenshots with quality < 100
+	"image/png"
+	"math"
+)
+
+const (
+	// DefaultThreshold is the default per-pixel color sensitivity (0 = exact, 1 = anything matches)
+	DefaultThreshold = 0.1
+
+	// maxYIQDelta is the largest possible squared YIQ distance between two colors
+	maxYIQDelta = 35215.0
+)
+
+var (
+	diffColor    = color.RGBA{R: 255, A: 255}
+	ignoreColor  = color.RGBA{R: 255, G: 200, A: 255}
+	sizeMismatch = color.RGBA{R: 255, B: 255, A: 255}
+)
+
+// Options configures an image comparison
+type Options struct {
+	// Threshold is the per-pixel color sensitivity from 0 (exact) to 1; negative values use
+	// DefaultThreshold. Pixels whose perceptual color distance is below it count as equal
+	Threshold float64
+	// MaxDiffRatio is the fraction of differing pixels tolerated before the images mismatch (default 0)
+	MaxDiffRatio float64
+	// Ignore lists regions, in image pixels, that are excluded from the comparison
+	Ignore []image.Rectangle
+}
+
+// Result holds the outcome of a comparison
+type Result struct {
+	// Match reports whether the images are equal within the configured tolerances
+	Match bool
+	// DiffPixels is the number of pixels that differ
+	DiffPixels int
+	// TotalPixels is the number of pixels compared (ignored regions excluded)
+	TotalPixels int
+	// DiffRatio is DiffPixels / TotalPixels
+	DiffRatio float64
+	// SizeMatch reports whether both images have the same dimensions
+	SizeMatch bool
+	// Diff is a visualization: a faded copy of the baseline with differing pixels in red,
+	// ignored regions in orange and pixels outside one of the images in magenta
+	Diff *image.RGBA
+}
+
+// Compare compares actual against baseline
+// Images of different sizes are compared over their union; pixels present in only one image count as different
+func Compare(baseline, actual image.Image, opts Options) *Result {
+	threshold := opts.Threshold
+	if threshold < 0 {
+		threshold = DefaultThreshold
+	}
+	maxDelta := maxYIQDelta * threshold * threshold
+
+	bb, ab := baseline.Bounds(), actual.Bounds()
+	width := max(bb.Dx(), ab.Dx())
+	height := max(bb.Dy(), ab.Dy())
+
+	diff := image.NewRGBA(image.Rect(0, 0, width, height))
+	result := &Result{
+		SizeMatch: bb.Dx() == ab.Dx() && bb.Dy() == ab.Dy(),
+		Diff:      diff,
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if ignored(opts.Ignore, x, y) {
+				diff.SetRGBA(x, y, ignoreColor)
+				continue
+			}
+			result.TotalPixels++
+
+			inBaseline := x < bb.Dx() && y < bb.Dy()
+			inActual := x < ab.Dx() && y < ab.Dy()
+			if !inBaseline || !inActual {
+				result.DiffPixels++
+				diff.SetRGBA(x, y, sizeMismatch)
+				continue
+			}
+
+			c1 := baseline.At(bb.Min.X+x, bb.Min.Y+y)
+			c2 := actual.At(ab.Min.X+x, ab.Min.Y+y)
+			if colorDelta(c1, c2) > maxDelta {
+				result.DiffPixels++
+				diff.SetRGBA(x, y, diffColor)
+			} else {
+				diff.SetRGBA(x, y, faded(c1))
+			}
+		}
+	}
+
+	if result.TotalPixels > 0 {
+		result.DiffRatio = float64(result.DiffPixels) / float64(result.TotalPixels)
+	}
+	result.Match = result.SizeMatch && result.DiffRatio <= opts.MaxDiffRatio
+
+	return result
+}
+
+// CompareBytes decodes two encoded images (PNG or JPEG) and compares them
+func CompareBytes(baseline, actual []byte, opts Options) (*Result, error) {
+	baseImg, _, err := image.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode baseline image: %w", err)
+	}
+	actualImg, _, err := image.Decode(bytes.NewReader(actual))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode actual image: %w", err)
+	}
+	return Compare(baseImg, actualImg, opts), nil
+}
+
+// EncodePNG encodes the diff visualization as PNG
+func (r *Result) EncodePNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, r.Diff); err != nil {
+		return nil, fmt.Errorf("failed to encode diff image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func ignored(regions []image.Rectangle, x, y int) bool {
+	p := image.Pt(x, y)
+	for _, r := range regions {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// colorDelta returns the squared perceptual (YIQ) distance between two colors,
+// after blending both onto a white background so transparency is taken into account
+func colorDelta(c1, c2 color.Color) float64 {
+	r1, g1, b1 := blendWhite(c1)
+	r2, g2, b2 := blendWhite(c2)
+
+	y := rgbToY(r1, g1, b1) - rgbToY(r2, g2, b2)
+	i := rgbToI(r1, g1, b1) - rgbToI(r2, g2, b2)
+	q := rgbToQ(r1, g1, b1) - rgbToQ(r2, g2, b2)
+
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func blendWhite(c color.Color) (r, g, b float64) {
+	nc, ok := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if !ok {
+		return 255, 255, 255
+	}
+	a := float64(nc.A) / 255
+	blend := func(v uint8) float64 { return 255 + (float64(v)-255)*a }
+	return blend(nc.R), blend(nc.G), blend(nc.B)
+}
+
+func rgbToY(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgbToI(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgbToQ(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+// faded returns a light grayscale version of c so differences stand out in the diff image
+func faded(c color.Color) color.RGBA {
+	r, g, b := blendWhite(c)
+	gray := uint8(math.Round(255 - (255-rgbToY(r, g, b))*0.1))
+	return color.RGBA{R: gray, G: gray, B: gray, A: 255}
+}
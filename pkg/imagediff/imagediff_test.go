@@ -0,0 +1,103 @@
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareIdentical(t *testing.T) {
+	a := solid(10, 10, color.White)
+	result := Compare(a, solid(10, 10, color.White), Options{})
+
+	if !result.Match || result.DiffPixels != 0 {
+		t.Errorf("expected identical images to match, got %d diff pixels", result.DiffPixels)
+	}
+}
+
+func TestCompareDetectsChanges(t *testing.T) {
+	baseline := solid(10, 10, color.White)
+	actual := solid(10, 10, color.White)
+	actual.Set(2, 3, color.Black)
+	actual.Set(7, 7, color.Black)
+
+	result := Compare(baseline, actual, Options{})
+	if result.Match {
+		t.Error("expected images to mismatch")
+	}
+	if result.DiffPixels != 2 {
+		t.Errorf("expected 2 diff pixels, got %d", result.DiffPixels)
+	}
+	if result.Diff.RGBAAt(2, 3) != diffColor {
+		t.Errorf("expected changed pixel to be marked in the diff image")
+	}
+
+	// A tolerated ratio lets small changes through
+	if !Compare(baseline, actual, Options{MaxDiffRatio: 0.05}).Match {
+		t.Error("expected 2%% difference to be within a 5%% tolerance")
+	}
+}
+
+func TestCompareThreshold(t *testing.T) {
+	baseline := solid(4, 4, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+	actual := solid(4, 4, color.RGBA{R: 203, G: 200, B: 200, A: 255})
+
+	if !Compare(baseline, actual, Options{Threshold: 0.1}).Match {
+		t.Error("expected a barely visible color shift to match at threshold 0.1")
+	}
+	if Compare(baseline, actual, Options{Threshold: 0.001}).Match {
+		t.Error("expected a color shift to mismatch at a strict threshold")
+	}
+	if !Compare(baseline, actual, Options{Threshold: -1}).Match {
+		t.Error("expected a negative threshold to use the default")
+	}
+}
+
+func TestCompareExactThreshold(t *testing.T) {
+	baseline := solid(4, 4, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+	actual := solid(4, 4, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+	actual.Set(1, 1, color.RGBA{R: 200, G: 200, B: 201, A: 255})
+
+	result := Compare(baseline, actual, Options{Threshold: 0})
+	if result.Match || result.DiffPixels != 1 {
+		t.Errorf("expected a 1-level color change to mismatch at threshold 0, got %d diff pixels", result.DiffPixels)
+	}
+	if !Compare(baseline, baseline, Options{Threshold: 0}).Match {
+		t.Error("expected identical images to match at threshold 0")
+	}
+}
+
+func TestCompareIgnoreRegions(t *testing.T) {
+	baseline := solid(10, 10, color.White)
+	actual := solid(10, 10, color.White)
+	actual.Set(5, 5, color.Black)
+
+	result := Compare(baseline, actual, Options{Ignore: []image.Rectangle{image.Rect(4, 4, 6, 6)}})
+	if !result.Match {
+		t.Errorf("expected ignored change to match, got %d diff pixels", result.DiffPixels)
+	}
+	if result.TotalPixels != 96 {
+		t.Errorf("expected 96 compared pixels, got %d", result.TotalPixels)
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	result := Compare(solid(10, 10, color.White), solid(10, 12, color.White), Options{MaxDiffRatio: 1})
+
+	if result.Match || result.SizeMatch {
+		t.Error("expected images of different sizes to mismatch")
+	}
+	if result.DiffPixels != 20 {
+		t.Errorf("expected 20 out-of-bounds pixels, got %d", result.DiffPixels)
+	}
+}
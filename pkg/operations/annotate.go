@@ -0,0 +1,126 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// BoundingBox is an element's position and size in CSS pixels
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Mark describes one numbered element in an annotated screenshot
+type Mark struct {
+	Index    int         `json:"index"`
+	Selector string      `json:"selector"`
+	Role     string      `json:"role"`
+	Text     string      `json:"text"`
+	Box      BoundingBox `json:"box"`
+}
+
+// annotateScript draws numbered labels over interactive elements and returns their legend.
+// Boxes are reported in screenshot coordinates: viewport-relative, or document-relative for full-page captures.
+const annotateScript = `
+(function(fullPage) {
+	%s
+
+	const interactive = [
+		'a[href]', 'button', 'input:not([type=hidden])', 'select', 'textarea', 'summary',
+		'[role=button]', '[role=link]', '[role=checkbox]', '[role=radio]', '[role=tab]',
+		'[role=menuitem]', '[role=option]', '[role=switch]', '[role=textbox]',
+		'[onclick]', '[contenteditable=""]', '[contenteditable=true]', '[tabindex]:not([tabindex="-1"])'
+	].join(',');
+
+	function isVisible(el, rect) {
+		if (rect.width === 0 || rect.height === 0) return false;
+		let style = getComputedStyle(el);
+		if (style.visibility === 'hidden' || style.display === 'none' || style.opacity === '0') return false;
+		if (fullPage) return true;
+		return rect.bottom > 0 && rect.right > 0 && rect.top < innerHeight && rect.left < innerWidth;
+	}
+
+	let old = document.getElementById('__browMarks');
+	if (old) old.remove();
+
+	let container = document.createElement('div');
+	container.id = '__browMarks';
+	container.style.cssText = 'position: absolute; top: 0; left: 0; pointer-events: none; z-index: 2147483647;';
+
+	let marks = [];
+	for (let el of document.querySelectorAll(interactive)) {
+		let rect = el.getBoundingClientRect();
+		if (!isVisible(el, rect)) continue;
+
+		let index = marks.length + 1;
+		let left = rect.left + scrollX;
+		let top = rect.top + scrollY;
+
+		let outline = document.createElement('div');
+		outline.style.cssText = 'position: absolute; border: 2px solid #ff0066; box-sizing: border-box;' +
+			'left: ' + left + 'px; top: ' + top + 'px; width: ' + rect.width + 'px; height: ' + rect.height + 'px;';
+		let label = document.createElement('div');
+		label.textContent = index;
+		label.style.cssText = 'position: absolute; background: #ff0066; color: white; font: bold 11px monospace;' +
+			'padding: 0 3px; line-height: 14px; left: ' + left + 'px; top: ' + Math.max(0, top - 14) + 'px;';
+		container.appendChild(outline);
+		container.appendChild(label);
+
+		marks.push({
+			index: index,
+			selector: getCSSSelector(el),
+			role: implicitRole(el),
+			text: labelText(el),
+			box: {
+				x: fullPage ? left : rect.left,
+				y: fullPage ? top : rect.top,
+				width: rect.width,
+				height: rect.height
+			}
+		});
+	}
+
+	document.documentElement.appendChild(container);
+	return marks;
+})(%t)
+`
+
+// AnnotatedScreenshot overlays numbered labels on interactive elements (links, buttons, inputs),
+// captures a screenshot and returns it together with a legend mapping label numbers to elements
+func AnnotatedScreenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, []Mark, error) {
+	var marks []Mark
+	script := fmt.Sprintf(annotateScript, selectorHelpersJS, opts.FullPage)
+
+	if err := chromedp.Run(ctx, evaluateIsolated(script, &marks)); err != nil {
+		return nil, nil, fmt.Errorf("failed to annotate page: %w", err)
+	}
+
+	buf, shotErr := CaptureScreenshot(ctx, opts)
+
+	// Always remove the overlay, even if the capture failed
+	removeScript := `(() => { let m = document.getElementById('__browMarks'); if (m) m.remove(); })()`
+	if err := chromedp.Run(ctx, evaluateIsolated(removeScript, nil)); err != nil && shotErr == nil {
+		return nil, nil, fmt.Errorf("failed to remove annotations: %w", err)
+	}
+
+	if shotErr != nil {
+		return nil, nil, shotErr
+	}
+
+	return buf, marks, nil
+}
+
+// FindMark returns the mark with the given index from a legend
+func FindMark(marks []Mark, index int) (*Mark, error) {
+	for i := range marks {
+		if marks[i].Index == index {
+			return &marks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no element #%d in the last annotated screenshot (legend has %d elements)", index, len(marks))
+}
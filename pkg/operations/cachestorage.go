@@ -0,0 +1,206 @@
+package operations
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cachestorage"
+	"github.com/chromedp/chromedp"
+)
+
+// cacheEntriesPageSize is the number of entries requested per CacheStorage.requestEntries call
+const cacheEntriesPageSize = 100
+
+// CacheInfo describes a Cache Storage cache
+type CacheInfo struct {
+	Origin  string `json:"origin"`
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+}
+
+// CacheEntry describes a request/response pair stored in a cache
+type CacheEntry struct {
+	URL          string            `json:"url"`
+	Method       string            `json:"method"`
+	Status       int64             `json:"status"`
+	StatusText   string            `json:"statusText,omitempty"`
+	Type         string            `json:"type"`
+	ResponseTime time.Time         `json:"responseTime"`
+	Headers      map[string]string `json:"headers,omitempty"`
+}
+
+// ListCaches lists the Cache Storage caches of an origin with their entry counts
+// An empty origin uses the current page's origin
+func ListCaches(ctx context.Context, origin string) ([]CacheInfo, error) {
+	var result []CacheInfo
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		caches, err := cacheNames(ctx, origin)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range caches {
+			_, count, err := cachestorage.RequestEntries(c.CacheID).WithPageSize(1).Do(ctx)
+			if err != nil {
+				return err
+			}
+			result = append(result, CacheInfo{
+				Origin:  c.SecurityOrigin,
+				Name:    c.CacheName,
+				Entries: int(count),
+			})
+		}
+		return nil
+	})); err != nil {
+		return nil, fmt.Errorf("failed to list caches: %w", err)
+	}
+
+	return result, nil
+}
+
+// CacheEntries lists the entries of a cache, optionally only those whose path contains pathFilter
+func CacheEntries(ctx context.Context, origin, cacheName, pathFilter string) ([]CacheEntry, error) {
+	var result []CacheEntry
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c, err := findCache(ctx, origin, cacheName)
+		if err != nil {
+			return err
+		}
+		entries, err := cacheDataEntries(ctx, c, pathFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			entry := CacheEntry{
+				URL:          e.RequestURL,
+				Method:       e.RequestMethod,
+				Status:       e.ResponseStatus,
+				StatusText:   e.ResponseStatusText,
+				Type:         e.ResponseType.String(),
+				ResponseTime: time.UnixMilli(int64(e.ResponseTime * 1000)),
+			}
+			if len(e.ResponseHeaders) > 0 {
+				entry.Headers = make(map[string]string, len(e.ResponseHeaders))
+				for _, h := range e.ResponseHeaders {
+					entry.Headers[h.Name] = h.Value
+				}
+			}
+			result = append(result, entry)
+		}
+		return nil
+	})); err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	return result, nil
+}
+
+// CachedResponseBody returns the body of the response cached for requestURL
+func CachedResponseBody(ctx context.Context, origin, cacheName, requestURL string) ([]byte, error) {
+	var body []byte
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c, err := findCache(ctx, origin, cacheName)
+		if err != nil {
+			return err
+		}
+		entries, err := cacheDataEntries(ctx, c, "")
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			if e.RequestURL != requestURL {
+				continue
+			}
+			// The original request headers are needed to match responses that vary on them
+			response, err := cachestorage.RequestCachedResponse(c.CacheID, e.RequestURL, e.RequestHeaders).Do(ctx)
+			if err != nil {
+				return err
+			}
+			body, err = base64.StdEncoding.DecodeString(response.Body)
+			return err
+		}
+		return fmt.Errorf("no entry for %s in cache %q", requestURL, cacheName)
+	})); err != nil {
+		return nil, fmt.Errorf("failed to get cached response: %w", err)
+	}
+
+	return body, nil
+}
+
+// DeleteCache deletes a whole cache, or only the entry for requestURL if it is set
+func DeleteCache(ctx context.Context, origin, cacheName, requestURL string) error {
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c, err := findCache(ctx, origin, cacheName)
+		if err != nil {
+			return err
+		}
+		if requestURL != "" {
+			return cachestorage.DeleteEntry(c.CacheID, requestURL).Do(ctx)
+		}
+		return cachestorage.DeleteCache(c.CacheID).Do(ctx)
+	})); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+
+	return nil
+}
+
+// cacheNames requests the caches of an origin, using the frame's storage key when the origin
+// is loaded in the tab and the plain security origin otherwise
+func cacheNames(ctx context.Context, origin string) ([]*cachestorage.Cache, error) {
+	params := cachestorage.RequestCacheNames()
+
+	_, key, err := originStorageKey(ctx, origin)
+	switch {
+	case err == nil:
+		params = params.WithStorageKey(string(key))
+	case errors.Is(err, ErrOriginNotLoaded):
+		params = params.WithSecurityOrigin(origin)
+	default:
+		return nil, err
+	}
+
+	return params.Do(ctx)
+}
+
+// findCache returns the cache named cacheName of an origin
+func findCache(ctx context.Context, origin, cacheName string) (*cachestorage.Cache, error) {
+	caches, err := cacheNames(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range caches {
+		if c.CacheName == cacheName {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("cache %q not found", cacheName)
+}
+
+// cacheDataEntries reads all entries of a cache, paging through CacheStorage.requestEntries
+func cacheDataEntries(ctx context.Context, c *cachestorage.Cache, pathFilter string) ([]*cachestorage.DataEntry, error) {
+	var entries []*cachestorage.DataEntry
+	for {
+		page, total, err := cachestorage.RequestEntries(c.CacheID).
+			WithSkipCount(int64(len(entries))).
+			WithPageSize(cacheEntriesPageSize).
+			WithPathFilter(pathFilter).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if len(page) == 0 || len(entries) >= int(total) {
+			return entries, nil
+		}
+	}
+}
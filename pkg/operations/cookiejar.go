@@ -0,0 +1,201 @@
+package operations
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// CookieFormat is a cookie file format
+type CookieFormat string
+
+const (
+	// CookieFormatJSON is a JSON array of network.Cookie, as printed by 'brow cookies'
+	CookieFormatJSON CookieFormat = "json"
+	// CookieFormatNetscape is the cookies.txt format used by curl, wget and yt-dlp
+	CookieFormatNetscape CookieFormat = "netscape"
+)
+
+// httpOnlyPrefix marks HttpOnly cookies in Netscape files (curl convention)
+const httpOnlyPrefix = "#HttpOnly_"
+
+// CookieMatchesDomain reports whether a cookie with cookieDomain is relevant to domain:
+// the cookie belongs to domain or one of its subdomains, or it is a domain cookie
+// (leading dot) that would be sent to domain
+func CookieMatchesDomain(cookieDomain, domain string) bool {
+	host := strings.ToLower(strings.TrimPrefix(domain, "."))
+	cd := strings.ToLower(strings.TrimPrefix(cookieDomain, "."))
+
+	if cd == host || strings.HasSuffix(cd, "."+host) {
+		return true
+	}
+	return strings.HasPrefix(cookieDomain, ".") && strings.HasSuffix(host, "."+cd)
+}
+
+// FormatCookies encodes cookies in the given format
+func FormatCookies(cookies []*network.Cookie, format CookieFormat) ([]byte, error) {
+	switch format {
+	case CookieFormatJSON:
+		data, err := json.MarshalIndent(cookies, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format cookies: %w", err)
+		}
+		return append(data, '\n'), nil
+	case CookieFormatNetscape:
+		return []byte(formatNetscape(cookies)), nil
+	default:
+		return nil, fmt.Errorf("unknown cookie format %q (expected json or netscape)", format)
+	}
+}
+
+// ParseCookies decodes a cookie file; an empty format detects JSON or Netscape from the content
+func ParseCookies(data []byte, format CookieFormat) ([]*network.Cookie, error) {
+	if format == "" {
+		format = CookieFormatNetscape
+		if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+			format = CookieFormatJSON
+		}
+	}
+
+	switch format {
+	case CookieFormatJSON:
+		var cookies []*network.Cookie
+		if err := json.Unmarshal(data, &cookies); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON cookies: %w", err)
+		}
+		return cookies, nil
+	case CookieFormatNetscape:
+		return parseNetscape(strings.NewReader(string(data)))
+	default:
+		return nil, fmt.Errorf("unknown cookie format %q (expected json or netscape)", format)
+	}
+}
+
+// formatNetscape writes cookies as a Netscape cookies.txt file
+// Fields: domain, include subdomains, path, secure, expiry (unix seconds, 0 = session), name, value
+func formatNetscape(cookies []*network.Cookie) string {
+	var b strings.Builder
+	b.WriteString("# Netscape HTTP Cookie File\n")
+	b.WriteString("# Exported by brow\n\n")
+
+	for _, c := range cookies {
+		domain := c.Domain
+		if c.HTTPOnly {
+			domain = httpOnlyPrefix + domain
+		}
+
+		var expires int64
+		if !c.Session && c.Expires > 0 {
+			expires = int64(c.Expires)
+		}
+
+		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain,
+			netscapeBool(strings.HasPrefix(c.Domain, ".")),
+			c.Path,
+			netscapeBool(c.Secure),
+			expires,
+			c.Name,
+			c.Value,
+		)
+	}
+
+	return b.String()
+}
+
+// parseNetscape reads a Netscape cookies.txt file
+func parseNetscape(r io.Reader) ([]*network.Cookie, error) {
+	var cookies []*network.Cookie
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if strings.HasPrefix(line, httpOnlyPrefix) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 {
+			// Some exporters drop the value field for empty values
+			fields = append(fields, "")
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab-separated fields, got %d", lineNo, len(fields))
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expiry %q", lineNo, fields[4])
+		}
+
+		domain := fields[0]
+		includeSubdomains := strings.EqualFold(fields[1], "TRUE")
+		if includeSubdomains && !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+
+		cookies = append(cookies, &network.Cookie{
+			Domain:   domain,
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Expires:  expires,
+			Session:  expires <= 0,
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookies: %w", err)
+	}
+
+	return cookies, nil
+}
+
+func netscapeBool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// cookieParam converts a stored cookie back into the parameters needed to set it
+func cookieParam(c *network.Cookie) *network.CookieParam {
+	param := &network.CookieParam{
+		Name:         c.Name,
+		Value:        c.Value,
+		Domain:       c.Domain,
+		Path:         c.Path,
+		Secure:       c.Secure,
+		HTTPOnly:     c.HTTPOnly,
+		SameSite:     c.SameSite,
+		Priority:     c.Priority,
+		PartitionKey: c.PartitionKey,
+	}
+	if param.Path == "" {
+		param.Path = "/"
+	}
+	if !c.Session && c.Expires > 0 {
+		sec := int64(c.Expires)
+		nsec := int64((c.Expires - float64(sec)) * float64(time.Second))
+		expires := cdp.TimeSinceEpoch(time.Unix(sec, nsec))
+		param.Expires = &expires
+	}
+	return param
+}
@@ -2,19 +2,54 @@ package operations
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/chromedp"
+	"golang.org/x/net/publicsuffix"
 )
 
-// GetCookies retrieves all cookies, optionally filtered by domain
+// CookieParams describes a cookie to set through the DevTools protocol
+// Unlike document.cookie, this can set HttpOnly cookies and cookies for any domain
+type CookieParams struct {
+	Name  string
+	Value string
+	// Domain the cookie belongs to; a leading dot (".example.com") includes subdomains.
+	// If both Domain and URL are empty, the current page's URL is used.
+	Domain string
+	// URL to associate the cookie with; determines the default domain, path and scheme
+	URL string
+	// Path defaults to "/" when Domain is set
+	Path string
+	// Expires is the expiry time; the zero value creates a session cookie
+	Expires  time.Time
+	HTTPOnly bool
+	Secure   bool
+	// SameSite is network.CookieSameSiteStrict, Lax or None (empty leaves Chrome's default)
+	SameSite network.CookieSameSite
+	// Partitioned makes a CHIPS cookie, stored per top-level site: PartitionKey, or the site of
+	// the current page if PartitionKey is empty
+	Partitioned bool
+	// PartitionKey is the top-level site of a partitioned cookie, e.g. "https://shop.example";
+	// setting it implies Partitioned
+	PartitionKey string
+}
+
+// GetCookies retrieves all browser cookies, optionally filtered by domain
+// The filter matches cookies for the domain and its subdomains, plus domain cookies
+// (".example.com") that the browser would send to it
 func GetCookies(ctx context.Context, domain string) ([]*network.Cookie, error) {
 	var cookies []*network.Cookie
 
 	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
-		allCookies, err := network.GetCookies().Do(ctx)
+		// Storage.getCookies returns cookies for every domain, not just the current page
+		allCookies, err := storage.GetCookies().Do(ctx)
 		if err != nil {
 			return err
 		}
@@ -22,7 +57,7 @@ func GetCookies(ctx context.Context, domain string) ([]*network.Cookie, error) {
 		// Filter by domain if specified
 		if domain != "" {
 			for _, cookie := range allCookies {
-				if cookie.Domain == domain || "."+cookie.Domain == domain {
+				if CookieMatchesDomain(cookie.Domain, domain) {
 					cookies = append(cookies, cookie)
 				}
 			}
@@ -38,24 +73,224 @@ func GetCookies(ctx context.Context, domain string) ([]*network.Cookie, error) {
 	return cookies, nil
 }
 
-// SetCookie sets a cookie using the document.cookie API
+// ImportCookies sets previously exported cookies, preserving all of their attributes
+func ImportCookies(ctx context.Context, cookies []*network.Cookie) error {
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, c := range cookies {
+		params = append(params, cookieParam(c))
+	}
+
+	if err := chromedp.Run(ctx, network.SetCookies(params)); err != nil {
+		return fmt.Errorf("failed to import cookies: %w", err)
+	}
+
+	return nil
+}
+
+// SetCookie sets a cookie from a Set-Cookie style string
 // The cookie parameter should be in the format: "name=value; domain=.example.com; path=/"
+// Supported attributes: Domain, Path, Expires, Max-Age, Secure, HttpOnly, SameSite and Partitioned
 func SetCookie(ctx context.Context, cookie string) error {
-	// Safely escape the cookie value using JSON encoding
-	cookieJSON, err := json.Marshal(cookie)
+	params, err := ParseCookie(cookie)
 	if err != nil {
-		return fmt.Errorf("failed to escape cookie value: %w", err)
+		return err
 	}
+	return SetCookieWithParams(ctx, *params)
+}
 
-	script := fmt.Sprintf("document.cookie = %s", string(cookieJSON))
+// ParseCookie parses a Set-Cookie style string into CookieParams
+// The value is kept as written, so it may contain quotes, commas, backslashes or spaces
+// (e.g. prefs={"a": 1}), which browsers accept from document.cookie but net/http rejects.
+func ParseCookie(cookie string) (*CookieParams, error) {
+	pair, attributes, _ := strings.Cut(cookie, ";")
+	name, value, ok := strings.Cut(pair, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid cookie %q (expected name=value; attribute=...)", cookie)
+	}
 
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, nil)); err != nil {
+	// net/http only parses response headers, so wrap the attributes in one with a placeholder
+	// name and value it accepts
+	header := http.Header{"Set-Cookie": {"brow=x;" + attributes}}
+	parsed := (&http.Response{Header: header}).Cookies()
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("invalid cookie attributes in %q", cookie)
+	}
+	c := parsed[0]
+
+	params := &CookieParams{
+		Name:     name,
+		Value:    strings.TrimSpace(value),
+		Domain:   c.Domain,
+		Path:     c.Path,
+		HTTPOnly: c.HttpOnly,
+		Secure:   c.Secure,
+	}
+
+	switch {
+	case c.MaxAge > 0:
+		params.Expires = time.Now().Add(time.Duration(c.MaxAge) * time.Second)
+	case c.MaxAge < 0:
+		// Max-Age=0 or negative expires the cookie immediately
+		params.Expires = time.Unix(1, 0)
+	case !c.Expires.IsZero():
+		params.Expires = c.Expires
+	}
+
+	switch c.SameSite {
+	case http.SameSiteStrictMode:
+		params.SameSite = network.CookieSameSiteStrict
+	case http.SameSiteLaxMode:
+		params.SameSite = network.CookieSameSiteLax
+	case http.SameSiteNoneMode:
+		params.SameSite = network.CookieSameSiteNone
+	}
+
+	// The partition is the top-level site the cookie is embedded under, not the cookie's own
+	// domain; SetCookieWithParams takes it from the current page
+	params.Partitioned = c.Partitioned
+
+	return params, nil
+}
+
+// SetCookieWithParams sets a cookie with Network.setCookie
+func SetCookieWithParams(ctx context.Context, params CookieParams) error {
+	if params.Name == "" {
+		return fmt.Errorf("cookie name is required")
+	}
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		cookieURL := params.URL
+		if params.Domain == "" && cookieURL == "" {
+			var err error
+			cookieURL, err = currentCookieURL(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		path := params.Path
+		if path == "" && params.Domain != "" {
+			path = "/"
+		}
+
+		action := network.SetCookie(params.Name, params.Value).
+			WithURL(cookieURL).
+			WithDomain(params.Domain).
+			WithPath(path).
+			WithHTTPOnly(params.HTTPOnly).
+			WithSecure(params.Secure).
+			WithSameSite(params.SameSite)
+
+		if !params.Expires.IsZero() {
+			expires := cdp.TimeSinceEpoch(params.Expires)
+			action = action.WithExpires(&expires)
+		}
+		if params.Partitioned || params.PartitionKey != "" {
+			topLevel := params.PartitionKey
+			if topLevel == "" {
+				var err error
+				if topLevel, err = currentCookieURL(ctx); err != nil {
+					return err
+				}
+			}
+			cookieHost := strings.TrimPrefix(params.Domain, ".")
+			if cookieHost == "" {
+				if u, err := url.Parse(cookieURL); err == nil {
+					cookieHost = u.Hostname()
+				}
+			}
+			key, err := cookiePartitionKey(topLevel, cookieHost)
+			if err != nil {
+				return err
+			}
+			action = action.WithPartitionKey(key)
+		}
+
+		return action.Do(ctx)
+	})); err != nil {
 		return fmt.Errorf("failed to set cookie: %w", err)
 	}
 
 	return nil
 }
 
+// cookiePartitionKey returns the partition of a cookie for cookieHost embedded under the page
+// at topLevelURL, marking it cross-site when the cookie's site differs from the page's site
+func cookiePartitionKey(topLevelURL, cookieHost string) (*network.CookiePartitionKey, error) {
+	u, err := url.Parse(topLevelURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid partition key %q (expected a top-level site such as https://shop.example)", topLevelURL)
+	}
+
+	topSite := registrableDomain(u.Hostname())
+	return &network.CookiePartitionKey{
+		TopLevelSite:         u.Scheme + "://" + topSite,
+		HasCrossSiteAncestor: cookieHost != "" && registrableDomain(cookieHost) != topSite,
+	}, nil
+}
+
+// registrableDomain reduces a host to its site, e.g. "app.shop.co.uk" to "shop.co.uk"; hosts
+// without a public suffix, such as localhost or IP addresses, are their own site
+func registrableDomain(host string) string {
+	host = strings.ToLower(host)
+	if site, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return site
+	}
+	return host
+}
+
+// currentCookieURL returns the page URL to scope a cookie to when no domain is given
+func currentCookieURL(ctx context.Context) (string, error) {
+	var location string
+	if err := chromedp.Location(&location).Do(ctx); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("cookie has no domain and the current page (%s) has no web origin; set a domain", location)
+	}
+	return location, nil
+}
+
+// DeleteCookie deletes cookies with the given name
+// If domain is set, only cookies for that domain (with or without a leading dot) are deleted;
+// otherwise the cookie is deleted for every domain. Returns the number of cookies deleted.
+func DeleteCookie(ctx context.Context, name, domain string) (int, error) {
+	deleted := 0
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		allCookies, err := storage.GetCookies().Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, cookie := range allCookies {
+			if cookie.Name != name {
+				continue
+			}
+			if domain != "" && strings.TrimPrefix(cookie.Domain, ".") != strings.TrimPrefix(domain, ".") {
+				continue
+			}
+
+			action := network.DeleteCookies(cookie.Name).
+				WithDomain(cookie.Domain).
+				WithPath(cookie.Path).
+				WithPartitionKey(cookie.PartitionKey)
+			if err := action.Do(ctx); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})); err != nil {
+		return 0, fmt.Errorf("failed to delete cookie: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // ClearCookies clears all browser cookies
 func ClearCookies(ctx context.Context) error {
 	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
@@ -0,0 +1,161 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestParseCookie(t *testing.T) {
+	params, err := ParseCookie("sid=abc123; Domain=.example.com; Path=/app; Secure; HttpOnly; SameSite=Strict; Max-Age=60")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params.Name != "sid" || params.Value != "abc123" {
+		t.Errorf("unexpected name/value: %s=%s", params.Name, params.Value)
+	}
+	if params.Domain != ".example.com" || params.Path != "/app" {
+		t.Errorf("unexpected domain/path: %s %s", params.Domain, params.Path)
+	}
+	if !params.Secure || !params.HTTPOnly {
+		t.Error("expected Secure and HttpOnly to be set")
+	}
+	if params.SameSite != network.CookieSameSiteStrict {
+		t.Errorf("expected SameSite Strict, got %q", params.SameSite)
+	}
+	if params.Expires.IsZero() {
+		t.Error("expected Max-Age to set an expiry")
+	}
+
+	if _, err := ParseCookie("not a cookie"); err == nil {
+		t.Error("expected an error for a string without name=value")
+	}
+	if _, err := ParseCookie("=value"); err == nil {
+		t.Error("expected an error for a cookie without a name")
+	}
+}
+
+func TestParseCookiePartitioned(t *testing.T) {
+	// A third-party widget cookie: the partition is the embedding site, not widget.example
+	params, err := ParseCookie("wid=1; Domain=widget.example; Path=/; Secure; SameSite=None; Partitioned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !params.Partitioned || params.PartitionKey != "" {
+		t.Errorf("expected a partitioned cookie keyed by the current page, got %+v", params)
+	}
+
+	tests := []struct {
+		topLevel   string
+		cookieHost string
+		want       network.CookiePartitionKey
+	}{
+		{"https://www.shop.test/cart", "widget.example", network.CookiePartitionKey{TopLevelSite: "https://shop.test", HasCrossSiteAncestor: true}},
+		{"https://shop.test", "cdn.shop.test", network.CookiePartitionKey{TopLevelSite: "https://shop.test"}},
+		{"https://app.shop.co.uk/", "shop.co.uk", network.CookiePartitionKey{TopLevelSite: "https://shop.co.uk"}},
+		{"http://localhost:8080/", "widget.example", network.CookiePartitionKey{TopLevelSite: "http://localhost", HasCrossSiteAncestor: true}},
+	}
+	for _, tt := range tests {
+		key, err := cookiePartitionKey(tt.topLevel, tt.cookieHost)
+		if err != nil {
+			t.Errorf("cookiePartitionKey(%q, %q): %v", tt.topLevel, tt.cookieHost, err)
+			continue
+		}
+		if *key != tt.want {
+			t.Errorf("cookiePartitionKey(%q, %q) = %+v, want %+v", tt.topLevel, tt.cookieHost, *key, tt.want)
+		}
+	}
+
+	if _, err := cookiePartitionKey("about:blank", "widget.example"); err == nil {
+		t.Error("expected an error for a top-level page without a web origin")
+	}
+}
+
+func TestParseCookieRawValue(t *testing.T) {
+	tests := []struct {
+		cookie string
+		value  string
+	}{
+		{`prefs={"a":1}`, `{"a":1}`},
+		{`list=a,b,c; Path=/`, `a,b,c`},
+		{`msg=hello world; Secure`, `hello world`},
+		{`path=C:\dir`, `C:\dir`},
+		{`token=abc==`, `abc==`},
+		{`empty=`, ``},
+	}
+
+	for _, tt := range tests {
+		params, err := ParseCookie(tt.cookie)
+		if err != nil {
+			t.Errorf("ParseCookie(%q): %v", tt.cookie, err)
+			continue
+		}
+		if params.Value != tt.value {
+			t.Errorf("ParseCookie(%q) value = %q, want %q", tt.cookie, params.Value, tt.value)
+		}
+	}
+
+	params, err := ParseCookie(`prefs={"a": 1}; Path=/app; Secure`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Path != "/app" || !params.Secure {
+		t.Errorf("attributes after a raw value were not parsed: %+v", params)
+	}
+}
+
+func TestCookieMatchesDomain(t *testing.T) {
+	tests := []struct {
+		cookieDomain string
+		filter       string
+		want         bool
+	}{
+		{"example.com", "example.com", true},
+		{".example.com", "example.com", true},
+		{"app.example.com", "example.com", true},
+		{".example.com", "app.example.com", true},
+		{"example.com", "app.example.com", false}, // host-only cookie is not sent to subdomains
+		{"notexample.com", "example.com", false},
+		{"example.com.evil.net", "example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := CookieMatchesDomain(tt.cookieDomain, tt.filter); got != tt.want {
+			t.Errorf("CookieMatchesDomain(%q, %q) = %v, want %v", tt.cookieDomain, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestNetscapeRoundTrip(t *testing.T) {
+	cookies := []*network.Cookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Secure: true, HTTPOnly: true, Expires: 1893456000},
+		{Name: "pref", Value: "dark", Domain: "app.example.com", Path: "/settings", Session: true, Expires: -1},
+	}
+
+	data, err := FormatCookies(cookies, CookieFormatNetscape)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseCookies(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed) != len(cookies) {
+		t.Fatalf("expected %d cookies, got %d", len(cookies), len(parsed))
+	}
+	for i, want := range cookies {
+		got := parsed[i]
+		if got.Name != want.Name || got.Value != want.Value || got.Domain != want.Domain || got.Path != want.Path {
+			t.Errorf("cookie %d: got %+v, want %+v", i, got, want)
+		}
+		if got.Secure != want.Secure || got.HTTPOnly != want.HTTPOnly || got.Session != want.Session {
+			t.Errorf("cookie %d: flags differ, got %+v, want %+v", i, got, want)
+		}
+	}
+	if parsed[0].Expires != cookies[0].Expires {
+		t.Errorf("expected expiry %v, got %v", cookies[0].Expires, parsed[0].Expires)
+	}
+}
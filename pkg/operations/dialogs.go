@@ -0,0 +1,203 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// pendingDialogWait bounds how long AnswerPendingDialog waits for the page to report a dialog
+const pendingDialogWait = 2 * time.Second
+
+// DialogAction is how a JavaScript dialog is answered
+type DialogAction string
+
+const (
+	// DialogAccept presses OK: confirm() returns true and prompt() the prompt text
+	DialogAccept DialogAction = "accept"
+	// DialogDismiss presses Cancel: confirm() returns false and prompt() null
+	DialogDismiss DialogAction = "dismiss"
+)
+
+// DialogPolicy answers dialogs automatically; the zero value leaves them open
+type DialogPolicy struct {
+	Action DialogAction
+	// PromptText answers accepted prompt() dialogs; empty uses the prompt's default value
+	PromptText string
+}
+
+// Dialog is a JavaScript dialog (alert, confirm, prompt or beforeunload) opened by a page
+type Dialog struct {
+	Type          string    `json:"type"`
+	Message       string    `json:"message"`
+	URL           string    `json:"url"`
+	DefaultPrompt string    `json:"defaultPrompt,omitempty"`
+	Time          time.Time `json:"time"`
+	// Action is how the dialog was answered, empty when it was left open
+	Action     DialogAction `json:"action,omitempty"`
+	PromptText string       `json:"promptText,omitempty"`
+}
+
+// ParseDialogPolicy parses "accept", "accept=<prompt text>", "dismiss" or "none"
+func ParseDialogPolicy(s string) (DialogPolicy, error) {
+	action, text, hasText := strings.Cut(s, "=")
+	switch DialogAction(action) {
+	case DialogAccept:
+		return DialogPolicy{Action: DialogAccept, PromptText: text}, nil
+	case DialogDismiss:
+		if !hasText {
+			return DialogPolicy{Action: DialogDismiss}, nil
+		}
+	case "", "none":
+		if !hasText {
+			return DialogPolicy{}, nil
+		}
+	}
+	return DialogPolicy{}, fmt.Errorf("invalid dialog policy %q: use accept, accept=<prompt text>, dismiss or none", s)
+}
+
+// String formats the policy the way ParseDialogPolicy reads it
+func (p DialogPolicy) String() string {
+	switch {
+	case p.Action == "":
+		return "none"
+	case p.Action == DialogAccept && p.PromptText != "":
+		return "accept=" + p.PromptText
+	default:
+		return string(p.Action)
+	}
+}
+
+// answer returns the dialog as answered by the policy and the command answering it
+func (p DialogPolicy) answer(d Dialog) (Dialog, *page.HandleJavaScriptDialogParams) {
+	if p.Action == "" {
+		return d, nil
+	}
+	d.Action = p.Action
+	params := page.HandleJavaScriptDialog(p.Action == DialogAccept)
+	if p.Action == DialogAccept && d.Type == string(page.DialogTypePrompt) {
+		d.PromptText = p.PromptText
+		if d.PromptText == "" {
+			d.PromptText = d.DefaultPrompt
+		}
+		params = params.WithPromptText(d.PromptText)
+	}
+	return d, params
+}
+
+// dialogFromEvent converts a Page.javascriptDialogOpening event
+func dialogFromEvent(e *page.EventJavascriptDialogOpening) Dialog {
+	return Dialog{
+		Type:          string(e.Type),
+		Message:       e.Message,
+		URL:           e.URL,
+		DefaultPrompt: e.DefaultPrompt,
+		Time:          time.Now(),
+	}
+}
+
+// HandleDialogs answers dialogs the page opens from now on according to policy and reports
+// each one to onDialog (which may be nil). Call it before the first action on a tab so a
+// dialog opened while brow attaches is caught too.
+func HandleDialogs(ctx context.Context, policy DialogPolicy, onDialog func(Dialog)) {
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		e, ok := ev.(*page.EventJavascriptDialogOpening)
+		if !ok {
+			return
+		}
+
+		// Listeners must not block; answering is a round trip to the browser
+		go func() {
+			d, params := policy.answer(dialogFromEvent(e))
+			if params != nil {
+				if err := chromedp.Run(ctx, params); err != nil {
+					d.Action = ""
+				}
+			}
+			if onDialog != nil {
+				onDialog(d)
+			}
+		}()
+	})
+}
+
+// HandleDialog answers the dialog currently open in the page
+func HandleDialog(ctx context.Context, accept bool, promptText string) error {
+	params := page.HandleJavaScriptDialog(accept)
+	if promptText != "" {
+		params = params.WithPromptText(promptText)
+	}
+	if err := chromedp.Run(ctx, params); err != nil {
+		return fmt.Errorf("failed to handle dialog: %w", err)
+	}
+	return nil
+}
+
+// AnswerPendingDialog answers a dialog that was already open before brow connected, which
+// blocks the page so that regular commands can't attach to it. It talks to the page target
+// over its own WebSocket (wsURL, e.g. ws://localhost:9222/devtools/page/<id>) and returns nil
+// when no dialog is open.
+func AnswerPendingDialog(ctx context.Context, wsURL string, policy DialogPolicy) (*Dialog, error) {
+	if policy.Action == "" {
+		return nil, fmt.Errorf("a dialog policy (accept or dismiss) is required")
+	}
+
+	conn, err := chromedp.DialContext(ctx, wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to page: %w", err)
+	}
+	// Conn.Read doesn't observe contexts; closing the connection ends a pending read
+	ctx, cancel := context.WithTimeout(ctx, pendingDialogWait)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	// Page.enable reports an open dialog; its reply is held back while the dialog blocks
+	// the page, so a reply means there is no dialog
+	if err := conn.Write(ctx, &cdproto.Message{ID: 1, Method: cdproto.MethodType(page.CommandEnable)}); err != nil {
+		return nil, fmt.Errorf("failed to query page: %w", err)
+	}
+
+	var dialog *Dialog
+	for {
+		var msg cdproto.Message
+		if err := conn.Read(ctx, &msg); err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("page isn't responding and reported no dialog")
+			}
+			return nil, fmt.Errorf("failed to read from page: %w", err)
+		}
+
+		switch {
+		case msg.ID == 1 && dialog == nil:
+			return nil, nil
+		case msg.Method == cdproto.EventPageJavascriptDialogOpening:
+			var e page.EventJavascriptDialogOpening
+			if err := json.Unmarshal(msg.Params, &e); err != nil {
+				return nil, fmt.Errorf("failed to decode dialog: %w", err)
+			}
+			answered, params := policy.answer(dialogFromEvent(&e))
+			dialog = &answered
+			encoded, err := json.Marshal(params)
+			if err != nil {
+				return nil, err
+			}
+			if err := conn.Write(ctx, &cdproto.Message{ID: 2, Method: cdproto.MethodType(page.CommandHandleJavaScriptDialog), Params: encoded}); err != nil {
+				return nil, fmt.Errorf("failed to handle dialog: %w", err)
+			}
+		case msg.ID == 2:
+			if msg.Error != nil {
+				return nil, fmt.Errorf("failed to handle dialog: %w", msg.Error)
+			}
+			return dialog, nil
+		}
+	}
+}
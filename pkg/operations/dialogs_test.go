@@ -0,0 +1,61 @@
+package operations
+
+import "testing"
+
+func TestParseDialogPolicy(t *testing.T) {
+	tests := []struct {
+		in   string
+		want DialogPolicy
+	}{
+		{"accept", DialogPolicy{Action: DialogAccept}},
+		{"accept=John Doe", DialogPolicy{Action: DialogAccept, PromptText: "John Doe"}},
+		{"accept=a=b", DialogPolicy{Action: DialogAccept, PromptText: "a=b"}},
+		{"dismiss", DialogPolicy{Action: DialogDismiss}},
+		{"none", DialogPolicy{}},
+		{"", DialogPolicy{}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDialogPolicy(tt.in)
+		if err != nil {
+			t.Fatalf("ParseDialogPolicy(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseDialogPolicy(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if tt.in != "" {
+			if again, _ := ParseDialogPolicy(got.String()); again != got {
+				t.Errorf("String() of %+v doesn't round-trip: %q", got, got.String())
+			}
+		}
+	}
+
+	for _, in := range []string{"ok", "dismiss=x", "none=x"} {
+		if _, err := ParseDialogPolicy(in); err == nil {
+			t.Errorf("ParseDialogPolicy(%q) should fail", in)
+		}
+	}
+}
+
+func TestDialogPolicyAnswer(t *testing.T) {
+	prompt := Dialog{Type: "prompt", Message: "Name?", DefaultPrompt: "guest"}
+
+	d, params := DialogPolicy{Action: DialogAccept}.answer(prompt)
+	if !params.Accept || params.PromptText != "guest" || d.PromptText != "guest" || d.Action != DialogAccept {
+		t.Errorf("accepting a prompt should answer its default, got %+v %+v", d, params)
+	}
+
+	d, params = DialogPolicy{Action: DialogAccept, PromptText: "Ada"}.answer(prompt)
+	if params.PromptText != "Ada" || d.PromptText != "Ada" {
+		t.Errorf("prompt text not used, got %+v %+v", d, params)
+	}
+
+	d, params = DialogPolicy{Action: DialogDismiss}.answer(prompt)
+	if params.Accept || params.PromptText != "" || d.Action != DialogDismiss {
+		t.Errorf("dismiss should cancel without text, got %+v %+v", d, params)
+	}
+
+	if d, params = (DialogPolicy{}).answer(prompt); params != nil || d.Action != "" {
+		t.Errorf("no policy should leave the dialog open, got %+v %+v", d, params)
+	}
+}
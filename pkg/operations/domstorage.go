@@ -0,0 +1,257 @@
+package operations
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chromedp/cdproto/domstorage"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrOriginNotLoaded is returned by origin-scoped storage functions when no frame
+// in the tab has the requested origin; Chrome only exposes storage of loaded origins
+var ErrOriginNotLoaded = errors.New("origin is not loaded in this tab")
+
+// StorageOrigin describes an origin loaded in a tab and how many storage items it has
+type StorageOrigin struct {
+	Origin       string `json:"origin"`
+	LocalItems   int    `json:"localStorage"`
+	SessionItems int    `json:"sessionStorage"`
+}
+
+// StorageOrigins lists the origins of all frames in the tab with their storage item counts
+func StorageOrigins(ctx context.Context) ([]StorageOrigin, error) {
+	var origins []StorageOrigin
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		frames, err := originFrames(ctx)
+		if err != nil {
+			return err
+		}
+		if err := domstorage.Enable().Do(ctx); err != nil {
+			return err
+		}
+
+		for origin, key := range frames {
+			local, err := domstorage.GetDOMStorageItems(storageID(key, true)).Do(ctx)
+			if err != nil {
+				return err
+			}
+			session, err := domstorage.GetDOMStorageItems(storageID(key, false)).Do(ctx)
+			if err != nil {
+				return err
+			}
+			origins = append(origins, StorageOrigin{
+				Origin:       origin,
+				LocalItems:   len(local),
+				SessionItems: len(session),
+			})
+		}
+		return nil
+	})); err != nil {
+		return nil, fmt.Errorf("failed to list storage origins: %w", err)
+	}
+
+	sort.Slice(origins, func(i, j int) bool { return origins[i].Origin < origins[j].Origin })
+	return origins, nil
+}
+
+// GetOriginStorage retrieves all items of an origin's storage through the DOMStorage domain
+// The origin must be loaded in a frame of the tab, otherwise ErrOriginNotLoaded is returned
+func GetOriginStorage(ctx context.Context, origin string, storageType StorageType) (map[string]string, error) {
+	items := make(map[string]string)
+
+	err := withOriginStorage(ctx, origin, storageType, func(ctx context.Context, id *domstorage.StorageID) error {
+		entries, err := domstorage.GetDOMStorageItems(id).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if len(entry) == 2 {
+				items[entry[0]] = entry[1]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage items: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetOriginStorageItems sets values in an origin's storage
+func SetOriginStorageItems(ctx context.Context, origin string, storageType StorageType, items map[string]string) error {
+	err := withOriginStorage(ctx, origin, storageType, func(ctx context.Context, id *domstorage.StorageID) error {
+		for k, v := range items {
+			if err := domstorage.SetDOMStorageItem(id, k, v).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set values: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveOriginStorageItem removes an item from an origin's storage
+func RemoveOriginStorageItem(ctx context.Context, origin string, storageType StorageType, key string) error {
+	err := withOriginStorage(ctx, origin, storageType, func(ctx context.Context, id *domstorage.StorageID) error {
+		return domstorage.RemoveDOMStorageItem(id, key).Do(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove item: %w", err)
+	}
+
+	return nil
+}
+
+// ClearOriginStorage clears an origin's storage
+func ClearOriginStorage(ctx context.Context, origin string, storageType StorageType) error {
+	err := withOriginStorage(ctx, origin, storageType, func(ctx context.Context, id *domstorage.StorageID) error {
+		return domstorage.Clear(id).Do(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear storage: %w", err)
+	}
+
+	return nil
+}
+
+// OpenOrigin navigates the tab to an empty document on origin without contacting the server
+// The document request is answered with a blank page through the Fetch domain, so the
+// site's scripts never run; this gives access to the origin's storage without side effects
+func OpenOrigin(ctx context.Context, origin string) error {
+	pattern := &fetch.RequestPattern{
+		URLPattern:   origin + "/*",
+		ResourceType: network.ResourceTypeDocument,
+		RequestStage: fetch.RequestStageRequest,
+	}
+	body := base64.StdEncoding.EncodeToString([]byte("<!DOCTYPE html><title></title>"))
+
+	if err := chromedp.Run(ctx, fetch.Enable().WithPatterns([]*fetch.RequestPattern{pattern})); err != nil {
+		return fmt.Errorf("failed to intercept requests: %w", err)
+	}
+	// Interception must end even if the navigation fails or ctx expires, or later navigations
+	// to origin would hang with nobody answering them
+	defer func() {
+		disableCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		_ = chromedp.Run(disableCtx, fetch.Disable())
+	}()
+
+	// The listener ends with this call, since a tab may open several origins in turn
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		e, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+		// Listeners must not block, so answer the request from a goroutine
+		go func() {
+			_ = chromedp.Run(ctx, fetch.FulfillRequest(e.RequestID, 200).
+				WithResponseHeaders([]*fetch.HeaderEntry{{Name: "Content-Type", Value: "text/html"}}).
+				WithBody(body))
+		}()
+	})
+
+	if err := chromedp.Run(ctx, chromedp.Navigate(origin+"/")); err != nil {
+		return fmt.Errorf("failed to open %s: %w", origin, err)
+	}
+
+	return nil
+}
+
+// withOriginStorage enables the DOMStorage domain and runs fn with the storage ID for origin
+func withOriginStorage(ctx context.Context, origin string, storageType StorageType, fn func(context.Context, *domstorage.StorageID) error) error {
+	return chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, key, err := originStorageKey(ctx, origin)
+		if err != nil {
+			return err
+		}
+
+		if err := domstorage.Enable().Do(ctx); err != nil {
+			return err
+		}
+		return fn(ctx, storageID(key, storageType == LocalStorage))
+	}))
+}
+
+// originStorageKey returns the storage key of a frame with origin; an empty origin selects
+// the origin of the tab's main frame
+func originStorageKey(ctx context.Context, origin string) (string, storage.SerializedStorageKey, error) {
+	if origin == "" {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		origin = tree.Frame.SecurityOrigin
+		if _, err := NormalizeOrigin(origin); err != nil {
+			return "", "", fmt.Errorf("current page has no web origin")
+		}
+	}
+
+	frames, err := originFrames(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, ok := frames[origin]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrOriginNotLoaded, origin)
+	}
+	return origin, key, nil
+}
+
+// originFrames maps the web origins of all frames in the tab to their storage keys
+func originFrames(ctx context.Context) (map[string]storage.SerializedStorageKey, error) {
+	tree, err := page.GetFrameTree().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make(map[string]storage.SerializedStorageKey)
+	var walk func(*page.FrameTree) error
+	walk = func(node *page.FrameTree) error {
+		origin := node.Frame.SecurityOrigin
+		if _, seen := frames[origin]; !seen {
+			if _, err := NormalizeOrigin(origin); err == nil {
+				key, err := storage.GetStorageKeyForFrame(node.Frame.ID).Do(ctx)
+				if err != nil {
+					return err
+				}
+				frames[origin] = key
+			}
+		}
+		for _, child := range node.ChildFrames {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(tree); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+func storageID(key storage.SerializedStorageKey, local bool) *domstorage.StorageID {
+	return &domstorage.StorageID{
+		StorageKey:     domstorage.SerializedStorageKey(key),
+		IsLocalStorage: local,
+	}
+}
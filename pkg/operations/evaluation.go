@@ -2,11 +2,52 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 )
 
+// evalArgsGlobal holds script arguments until the script copies them into constants
+const evalArgsGlobal = "__browEvalArgs"
+
+// ErrIncompleteScript is returned by CheckSyntax for input that ends inside a block, call,
+// template literal or comment, where a console should read more lines
+var ErrIncompleteScript = errors.New("incomplete script")
+
+// argNamePattern matches names usable as JavaScript constants
+var argNamePattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// EvalOptions configures EvaluateWithOptions
+type EvalOptions struct {
+	// Args are passed to the script as constants named after their keys; values travel
+	// as JSON through the protocol and are never spliced into the source
+	Args map[string]interface{}
+	// AwaitPromise waits for a returned promise to settle and returns its value
+	AwaitPromise bool
+	// Timeout bounds the evaluation, including awaiting a promise (0 means no limit)
+	Timeout time.Duration
+	// Frame runs the script in an iframe chosen by name, URL substring or iframe selector
+	Frame string
+	// Isolated runs the script in brow's isolated world, which shares the DOM but not the
+	// page's JavaScript globals, so pages that replace built-ins can't interfere
+	Isolated bool
+	// Rich keeps values JSON can't represent instead of turning them into null or {}: DOM
+	// nodes, Map, Set, BigInt, undefined, NaN, dates, errors and cycles come back as objects
+	// tagged with a "$brow" type, e.g. {"$brow": "node", "selector": "#main", "html": "<main>…"}
+	Rich bool
+	// ReplMode evaluates like the DevTools console: top-level await is allowed and let and
+	// const declarations from earlier evaluations can be redeclared
+	ReplMode bool
+}
+
 // Evaluate executes JavaScript in the page context and returns the result
 func Evaluate(ctx context.Context, script string) (interface{}, error) {
 	var result interface{}
@@ -15,3 +56,223 @@ func Evaluate(ctx context.Context, script string) (interface{}, error) {
 	}
 	return result, nil
 }
+
+// EvaluateInFrame executes JavaScript in a frame chosen by name, URL substring or iframe
+// selector (see ListFrames), in the same context as the frame's own scripts. Cross-origin
+// and out-of-process iframes are supported.
+func EvaluateInFrame(ctx context.Context, frame, script string) (interface{}, error) {
+	return EvaluateWithOptions(ctx, script, EvalOptions{Frame: frame})
+}
+
+// EvaluateIsolated executes JavaScript in an isolated world of the main frame: it sees the
+// DOM and storage but not the page's globals, and its own globals persist until navigation
+func EvaluateIsolated(ctx context.Context, script string) (interface{}, error) {
+	return EvaluateWithOptions(ctx, script, EvalOptions{Isolated: true})
+}
+
+// EvaluateInto executes JavaScript and decodes the result into dst, awaiting a returned
+// promise. Values are serialized richly and then simplified for Go types: BigInts decode into
+// integers, dates into strings or time.Time, Maps into maps or structs, Sets into slices and
+// nodes into NodeValue; undefined and NaN become zero values.
+func EvaluateInto(ctx context.Context, script string, dst interface{}) error {
+	result, err := EvaluateWithOptions(ctx, script, EvalOptions{AwaitPromise: true, Rich: true})
+	if err != nil {
+		return err
+	}
+	return decodeInto(result, dst)
+}
+
+// EvaluateWithOptions executes JavaScript with arguments, promise awaiting, a timeout or in
+// an iframe; the script's completion value is returned as with Evaluate
+func EvaluateWithOptions(ctx context.Context, script string, opts EvalOptions) (interface{}, error) {
+	callerCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	runCtx := ctx
+	var world runtime.ExecutionContextID
+	if opts.Frame != "" || opts.Isolated {
+		frame, err := resolveFrame(ctx, opts.Frame)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Isolated {
+			world, err = frame.isolatedWorld()
+		} else {
+			world, err = frame.mainWorld()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Out-of-process frames have their own context; carry the deadline over
+		runCtx = frame.ctx
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithDeadline(runCtx, deadline)
+			defer cancel()
+		}
+	}
+
+	if len(opts.Args) > 0 {
+		var err error
+		if script, err = withArgs(runCtx, world, script, opts.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	var evalOpts []chromedp.EvaluateOption
+	if world != 0 {
+		evalOpts = append(evalOpts, inContext(world))
+	}
+	if opts.AwaitPromise {
+		evalOpts = append(evalOpts, awaitPromise)
+	}
+	if opts.ReplMode {
+		evalOpts = append(evalOpts, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithReplMode(true)
+		})
+	}
+	if opts.Timeout > 0 {
+		// Also stops synchronous code such as endless loops inside the page
+		evalOpts = append(evalOpts, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithTimeout(runtime.TimeDelta(opts.Timeout.Milliseconds()))
+		})
+	}
+
+	var result interface{}
+	action := chromedp.Evaluate(script, &result, evalOpts...)
+	if opts.Rich {
+		action = evaluateRich(script, &result, evalOpts...)
+	}
+	if err := chromedp.Run(runCtx, action); err != nil {
+		// Only report the timeout when it was opts.Timeout, not the caller's deadline
+		if opts.Timeout > 0 && errors.Is(runCtx.Err(), context.DeadlineExceeded) && callerCtx.Err() == nil {
+			return nil, fmt.Errorf("script did not finish within %s", opts.Timeout)
+		}
+		return nil, fmt.Errorf("failed to evaluate JavaScript: %w", err)
+	}
+	return result, nil
+}
+
+// CheckSyntax compiles script in the page without running it and returns its syntax error,
+// or ErrIncompleteScript when the script is only cut short
+func CheckSyntax(ctx context.Context, script string) error {
+	var exception *runtime.ExceptionDetails
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		_, exception, err = runtime.CompileScript(script, "", false).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to compile JavaScript: %w", err)
+	}
+	if exception == nil {
+		return nil
+	}
+
+	message := exception.Text
+	if exception.Exception != nil && exception.Exception.Description != "" {
+		message = exception.Exception.Description
+	}
+	if strings.Contains(message, "Unexpected end of input") || strings.Contains(message, "Unterminated template literal") {
+		return ErrIncompleteScript
+	}
+	return errors.New(message)
+}
+
+// InterruptScript terminates JavaScript currently running in the page, such as an endless
+// loop, by throwing an uncatchable exception into it
+func InterruptScript(ctx context.Context) error {
+	if err := chromedp.Run(ctx, runtime.TerminateExecution()); err != nil {
+		return fmt.Errorf("failed to interrupt script: %w", err)
+	}
+	return nil
+}
+
+// withArgs stores args on the global object through Runtime.callFunctionOn and wraps the
+// script in a block that copies them into constants before it runs
+func withArgs(ctx context.Context, world runtime.ExecutionContextID, script string, args map[string]interface{}) (string, error) {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		if !argNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid argument name %q: must be a JavaScript identifier", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		evaluate := runtime.Evaluate("globalThis")
+		if world != 0 {
+			evaluate = evaluate.WithContextID(world)
+		}
+		global, exception, err := evaluate.Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		defer func() { _ = runtime.ReleaseObject(global.ObjectID).Do(ctx) }()
+
+		_, exception, err = runtime.CallFunctionOn(`function(args) {
+			Object.defineProperty(this, '` + evalArgsGlobal + `', { value: args, configurable: true });
+		}`).
+			WithObjectID(global.ObjectID).
+			WithArguments([]*runtime.CallArgument{{Value: encoded}}).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return nil
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to pass arguments: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "const %s = globalThis.%s[%q];\n", name, evalArgsGlobal, name)
+	}
+	fmt.Fprintf(&b, "delete globalThis.%s;\n", evalArgsGlobal)
+	b.WriteString(script)
+	b.WriteString("\n}")
+	return b.String(), nil
+}
+
+// inContext makes Runtime.evaluate run in the given execution context
+func inContext(id runtime.ExecutionContextID) chromedp.EvaluateOption {
+	return func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithContextID(id)
+	}
+}
+
+// evaluateIsolated is chromedp.Evaluate in brow's isolated world of the main frame, used by
+// brow's own helpers so page scripts that replace built-ins (JSON, Array.from) can't break them
+func evaluateIsolated(script string, res interface{}, opts ...chromedp.EvaluateOption) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		world, err := page.CreateIsolatedWorld(tree.Frame.ID).WithWorldName(isolatedWorldName).Do(ctx)
+		if err != nil {
+			return err
+		}
+		evalOpts := append(opts[:len(opts):len(opts)], inContext(world))
+		return chromedp.Evaluate(script, res, evalOpts...).Do(ctx)
+	})
+}
@@ -0,0 +1,753 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// isolatedWorldName names the isolated world brow runs its helpers in, so page scripts
+// can't see or break them; Chrome reuses the world for the same name until the frame navigates
+const isolatedWorldName = "__brow"
+
+// deepSeparator steps from an iframe into its document, or from a shadow host into its
+// open shadow root, e.g. "iframe#checkout >>> my-card >>> button.pay"
+const deepSeparator = ">>>"
+
+// frameContextBinding is called from a frame's main world to reveal its execution context
+const frameContextBinding = "__browFrameContext"
+
+// errNoMatch is returned while a deep selector doesn't match yet
+var errNoMatch = errors.New("no element matches")
+
+// Frame describes one frame of a tab
+type Frame struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parentId,omitempty"`
+	Name     string `json:"name,omitempty"`
+	URL      string `json:"url"`
+	// Selector is a CSS selector (with " >>> " steps when nested) for the iframe element
+	// holding the frame; empty for the main frame
+	Selector string `json:"selector,omitempty"`
+	// OutOfProcess is set for cross-site iframes rendered by their own process (OOPIF)
+	OutOfProcess bool `json:"outOfProcess,omitempty"`
+	// Depth is 0 for the main frame, 1 for its iframes and so on
+	Depth int `json:"depth"`
+}
+
+// frameHandle addresses one frame of a tab
+type frameHandle struct {
+	// ctx runs commands on the target rendering the frame: the tab itself, or the own
+	// target of an out-of-process iframe (OOPIF)
+	ctx    context.Context
+	id     cdp.FrameID
+	name   string
+	url    string
+	parent *frameHandle
+	// targetRoot is set for the frame at the root of its target (the main frame or an OOPIF)
+	targetRoot bool
+}
+
+// oopifTarget is an attachment to the target of an out-of-process iframe
+type oopifTarget struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var (
+	oopifMu sync.Mutex
+	// oopifTargets caches attachments per browser connection until the connection is lost
+	oopifTargets = map[*chromedp.Browser]map[target.ID]oopifTarget{}
+)
+
+// oopifContext attaches to the target of an out-of-process iframe, which chromedp doesn't do
+// on its own. The context is detached from ctx's cancellation, because cancelling a chromedp
+// context closes its target, and closing an iframe's target closes the whole tab. Attachments
+// are cached per connection and released once the connection to the browser is lost.
+func oopifContext(ctx context.Context, id target.ID) (context.Context, error) {
+	c := chromedp.FromContext(ctx)
+	if c == nil || c.Browser == nil {
+		return nil, fmt.Errorf("failed to attach to iframe %s: not connected", id)
+	}
+
+	oopifMu.Lock()
+	defer oopifMu.Unlock()
+
+	attached, ok := oopifTargets[c.Browser]
+	if !ok {
+		attached = map[target.ID]oopifTarget{}
+		oopifTargets[c.Browser] = attached
+		go releaseOOPIFTargets(c.Browser)
+	}
+	if t, ok := attached[id]; ok {
+		return t.ctx, nil
+	}
+
+	frameCtx, cancel := chromedp.NewContext(context.WithoutCancel(ctx), chromedp.WithTargetID(id))
+	if err := chromedp.Run(frameCtx); err != nil {
+		// Cancelling a half-attached target could close the tab; release it with the connection
+		go func(browser *chromedp.Browser) {
+			<-browser.LostConnection
+			cancel()
+		}(c.Browser)
+		return nil, fmt.Errorf("failed to attach to iframe %s: %w", id, err)
+	}
+	attached[id] = oopifTarget{ctx: frameCtx, cancel: cancel}
+	return frameCtx, nil
+}
+
+// releaseOOPIFTargets waits for the connection to end and then cancels its iframe contexts,
+// which stops their event loops; with the connection gone, the iframes stay open in Chrome
+func releaseOOPIFTargets(browser *chromedp.Browser) {
+	<-browser.LostConnection
+
+	oopifMu.Lock()
+	attached := oopifTargets[browser]
+	delete(oopifTargets, browser)
+	oopifMu.Unlock()
+
+	for _, t := range attached {
+		t.cancel()
+	}
+}
+
+// getFrameTree returns the frame tree of the target ctx is attached to
+func getFrameTree(ctx context.Context) (*page.FrameTree, error) {
+	var tree *page.FrameTree
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		tree, err = page.GetFrameTree().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frame tree: %w", err)
+	}
+	return tree, nil
+}
+
+// mainFrame returns the tab's main frame
+func mainFrame(ctx context.Context) (*frameHandle, error) {
+	tree, err := getFrameTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &frameHandle{ctx: ctx, id: tree.Frame.ID, url: tree.Frame.URL, targetRoot: true}, nil
+}
+
+// ListFrames returns the tab's frame tree in depth-first order, including out-of-process iframes
+func ListFrames(ctx context.Context) ([]Frame, error) {
+	handles, err := tabFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]Frame, 0, len(handles))
+	depths := map[*frameHandle]int{}
+	for _, f := range handles {
+		frame := Frame{
+			ID:           string(f.id),
+			Name:         f.name,
+			URL:          f.url,
+			OutOfProcess: f.targetRoot && f.parent != nil,
+		}
+		if f.parent != nil {
+			frame.ParentID = string(f.parent.id)
+			frame.Depth = depths[f.parent] + 1
+			if prefix, err := f.selectorPrefix(); err == nil {
+				frame.Selector = strings.TrimSuffix(prefix, " "+deepSeparator+" ")
+			}
+		}
+		depths[f] = frame.Depth
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// resolveFrame finds the frame for a spec: a frame name, a substring of the frame's URL or a
+// CSS selector (which may be deep) of its iframe element, tried in that order. An empty spec
+// is the main frame.
+func resolveFrame(ctx context.Context, spec string) (*frameHandle, error) {
+	if spec == "" {
+		return mainFrame(ctx)
+	}
+
+	frames, err := tabFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range frames[1:] {
+		if f.name == spec {
+			return f, nil
+		}
+	}
+	for _, f := range frames[1:] {
+		if strings.Contains(f.url, spec) {
+			return f, nil
+		}
+	}
+
+	owner, element, err := frames[0].query(spec)
+	if err != nil {
+		return nil, fmt.Errorf("no frame matches %q by name, URL or iframe selector", spec)
+	}
+	frame, err := owner.childFrame(element)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an iframe: %w", spec, err)
+	}
+	return frame, nil
+}
+
+// tabFrames lists all frames of the tab in tree order, attaching to out-of-process iframes
+func tabFrames(ctx context.Context) ([]*frameHandle, error) {
+	tree, err := getFrameTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Out-of-process iframes are separate targets whose ID is their frame ID
+	targets, err := chromedp.Targets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list targets: %w", err)
+	}
+	iframes := map[cdp.FrameID]bool{}
+	for _, t := range targets {
+		if t.Type == "iframe" {
+			iframes[cdp.FrameID(t.TargetID)] = true
+		}
+	}
+
+	var frames []*frameHandle
+	if err := collectFrames(ctx, tree, nil, iframes, &frames); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// collectFrames appends the frames of one target's frame tree, descending into the
+// out-of-process iframes they own, which don't appear in the tree
+func collectFrames(ctx context.Context, tree *page.FrameTree, parent *frameHandle, iframes map[cdp.FrameID]bool, frames *[]*frameHandle) error {
+	var walk func(t *page.FrameTree, parent *frameHandle, root bool) error
+	walk = func(t *page.FrameTree, parent *frameHandle, root bool) error {
+		f := &frameHandle{
+			ctx:        ctx,
+			id:         t.Frame.ID,
+			name:       t.Frame.Name,
+			url:        t.Frame.URL + t.Frame.URLFragment,
+			parent:     parent,
+			targetRoot: root,
+		}
+		*frames = append(*frames, f)
+
+		for _, child := range t.ChildFrames {
+			if err := walk(child, f, false); err != nil {
+				return err
+			}
+		}
+
+		if len(iframes) == 0 {
+			return nil
+		}
+		children, err := f.childFrameIDs()
+		if err != nil {
+			// The frame may have navigated or been detached meanwhile
+			return nil
+		}
+		for _, id := range children {
+			if !iframes[id] {
+				continue
+			}
+			frameCtx, err := oopifContext(ctx, target.ID(id))
+			if err != nil {
+				return err
+			}
+			subtree, err := getFrameTree(frameCtx)
+			if err != nil {
+				return err
+			}
+			if err := collectFrames(frameCtx, subtree, f, iframes, frames); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(tree, parent, true)
+}
+
+// childFramesScript collects the iframe elements of a document, including inside open shadow roots
+const childFramesScript = `(function() {
+	const found = [];
+	(function walk(root) {
+		for (const el of root.querySelectorAll('*')) {
+			if (el.tagName === 'IFRAME' || el.tagName === 'FRAME') found.push(el);
+			if (el.shadowRoot) walk(el.shadowRoot);
+		}
+	})(document);
+	return found;
+})()`
+
+// childFrameIDs returns the frame IDs of the iframes in the frame's document
+func (f *frameHandle) childFrameIDs() ([]cdp.FrameID, error) {
+	list, err := f.evaluate(childFramesScript, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []cdp.FrameID
+	err = f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		defer func() { _ = runtime.ReleaseObject(list.ObjectID).Do(ctx) }()
+		props, _, _, _, err := runtime.GetProperties(list.ObjectID).WithOwnProperties(true).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, prop := range props {
+			if prop.Value == nil || prop.Value.ObjectID == "" {
+				continue
+			}
+			node, err := dom.DescribeNode().WithObjectID(prop.Value.ObjectID).Do(ctx)
+			if err != nil {
+				return err
+			}
+			if node.FrameID != "" {
+				ids = append(ids, node.FrameID)
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child frames: %w", err)
+	}
+	return ids, nil
+}
+
+// run executes actions on the frame's target
+func (f *frameHandle) run(actions ...chromedp.Action) error {
+	return chromedp.Run(f.ctx, actions...)
+}
+
+// isolatedWorld returns the execution context of brow's isolated world in the frame
+func (f *frameHandle) isolatedWorld() (runtime.ExecutionContextID, error) {
+	var id runtime.ExecutionContextID
+	err := f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		id, err = page.CreateIsolatedWorld(f.id).WithWorldName(isolatedWorldName).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create isolated world: %w", err)
+	}
+	return id, nil
+}
+
+// evaluate runs expression in the frame's isolated world, awaiting promises
+func (f *frameHandle) evaluate(expression string, returnByValue bool) (*runtime.RemoteObject, error) {
+	world, err := f.isolatedWorld()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *runtime.RemoteObject
+	err = f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		res, exception, err := runtime.Evaluate(expression).
+			WithContextID(world).
+			WithReturnByValue(returnByValue).
+			WithAwaitPromise(true).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		result = res
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mainWorld returns the execution context the frame's own scripts run in. Chrome has no
+// command for it, so a temporary binding is called from the frame's document resolved in
+// the main world, and the binding event reports the context.
+func (f *frameHandle) mainWorld() (runtime.ExecutionContextID, error) {
+	doc, err := f.evaluate("document", false)
+	if err != nil {
+		return 0, err
+	}
+
+	contexts := make(chan runtime.ExecutionContextID, 1)
+	listenCtx, cancel := context.WithCancel(f.ctx)
+	defer cancel()
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		if e, ok := ev.(*runtime.EventBindingCalled); ok && e.Name == frameContextBinding {
+			select {
+			case contexts <- e.ExecutionContextID:
+			default:
+			}
+		}
+	})
+
+	name, err := json.Marshal(frameContextBinding)
+	if err != nil {
+		return 0, err
+	}
+	err = f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		defer func() { _ = runtime.ReleaseObject(doc.ObjectID).Do(ctx) }()
+		node, err := dom.DescribeNode().WithObjectID(doc.ObjectID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		mainDoc, err := dom.ResolveNode().WithBackendNodeID(node.BackendNodeID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = runtime.ReleaseObject(mainDoc.ObjectID).Do(ctx) }()
+
+		if err := runtime.AddBinding(frameContextBinding).Do(ctx); err != nil {
+			return err
+		}
+		defer func() { _ = runtime.RemoveBinding(frameContextBinding).Do(ctx) }()
+
+		_, exception, err := runtime.CallFunctionOn(`function(name) { window[name](''); }`).
+			WithObjectID(mainDoc.ObjectID).
+			WithArguments([]*runtime.CallArgument{{Value: name}}).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return nil
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the frame's execution context: %w", err)
+	}
+
+	select {
+	case id := <-contexts:
+		return id, nil
+	case <-time.After(5 * time.Second):
+		return 0, errors.New("failed to find the frame's execution context: no response")
+	}
+}
+
+// ownerSelector returns a CSS selector for the iframe element that holds the frame, relative
+// to the parent frame's document
+func (f *frameHandle) ownerSelector() (string, error) {
+	world, err := f.parent.isolatedWorld()
+	if err != nil {
+		return "", err
+	}
+
+	var selector string
+	err = f.parent.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		backendID, _, err := dom.GetFrameOwner(f.id).Do(ctx)
+		if err != nil {
+			return err
+		}
+		owner, err := dom.ResolveNode().WithBackendNodeID(backendID).WithExecutionContextID(world).Do(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = runtime.ReleaseObject(owner.ObjectID).Do(ctx) }()
+
+		res, exception, err := runtime.CallFunctionOn("function() {\n" + selectorHelpersJS + "\nreturn getCSSSelector(this);\n}").
+			WithObjectID(owner.ObjectID).
+			WithReturnByValue(true).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return json.Unmarshal(res.Value, &selector)
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to build selector for frame %s: %w", f.id, err)
+	}
+	return selector, nil
+}
+
+// selectorPrefix returns the deep selector of the iframes leading to the frame, ending in
+// " >>> " (empty for the main frame)
+func (f *frameHandle) selectorPrefix() (string, error) {
+	if f.parent == nil {
+		return "", nil
+	}
+	prefix, err := f.parent.selectorPrefix()
+	if err != nil {
+		return "", err
+	}
+	owner, err := f.ownerSelector()
+	if err != nil {
+		return "", err
+	}
+	return prefix + owner + " " + deepSeparator + " ", nil
+}
+
+// contentOrigin returns the top-left corner of the frame's content in the tab's viewport;
+// coordinates from inside the frame (getBoundingClientRect) are relative to it
+func (f *frameHandle) contentOrigin() (float64, float64, error) {
+	if f.parent == nil {
+		return 0, 0, nil
+	}
+
+	var model *dom.BoxModel
+	err := f.parent.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		backendID, _, err := dom.GetFrameOwner(f.id).Do(ctx)
+		if err != nil {
+			return err
+		}
+		model, err = dom.GetBoxModel().WithBackendNodeID(backendID).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to locate frame %s: %w", f.id, err)
+	}
+
+	x, y, err := f.parent.targetOrigin()
+	if err != nil {
+		return 0, 0, err
+	}
+	return x + model.Content[0], y + model.Content[1], nil
+}
+
+// targetOrigin returns where the viewport of the frame's target starts in the tab's viewport;
+// DOM geometry reported by the target (box models, quads) is relative to it
+func (f *frameHandle) targetOrigin() (float64, float64, error) {
+	root := f
+	for !root.targetRoot {
+		root = root.parent
+	}
+	return root.contentOrigin()
+}
+
+// deepQueryScript resolves the parts of a deep selector within one document; it stops at an
+// iframe so the caller can continue in the iframe's frame, which may be out of process
+const deepQueryScript = `(function(parts) {
+	let root = document;
+	for (let i = 0; i < parts.length; i++) {
+		const el = root.querySelector(parts[i]);
+		if (!el) return [null, 'missing', i];
+		if (i === parts.length - 1) return [el, 'found', i];
+		if (el.tagName === 'IFRAME' || el.tagName === 'FRAME') return [el, 'frame', i];
+		if (!el.shadowRoot) return [null, 'closed', i];
+		root = el.shadowRoot;
+	}
+})(%s)`
+
+// IsDeepSelector reports whether a selector uses " >>> " to enter iframes or shadow roots
+func IsDeepSelector(selector string) bool {
+	return len(splitSelector(selector, deepSeparator)) > 1
+}
+
+// splitSelector splits a selector on sep outside quotes, brackets and parentheses
+func splitSelector(selector, sep string) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(selector); i++ {
+		ch := selector[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[' || ch == '(':
+			depth++
+		case ch == ']' || ch == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(selector[i:], sep):
+			parts = append(parts, strings.TrimSpace(selector[start:i]))
+			start = i + len(sep)
+			i = start - 1
+		}
+	}
+	return append(parts, strings.TrimSpace(selector[start:]))
+}
+
+// query resolves a (deep) selector once from the frame's document, following iframes across
+// frames and processes, and returns the element's frame and object in brow's isolated world
+func (f *frameHandle) query(selector string) (*frameHandle, runtime.RemoteObjectID, error) {
+	frame := f
+	parts := splitSelector(selector, deepSeparator)
+	done := 0
+	for {
+		element, status, index, err := frame.queryParts(parts[done:])
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to resolve %s: %w", selector, err)
+		}
+		step := strings.Join(parts[:done+index+1], " "+deepSeparator+" ")
+
+		switch status {
+		case "found":
+			return frame, element, nil
+		case "missing":
+			return nil, "", fmt.Errorf("%w %s", errNoMatch, step)
+		case "closed":
+			return nil, "", fmt.Errorf("%s is neither an iframe nor a host with an open shadow root", step)
+		}
+
+		frame, err = frame.childFrame(element)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to enter %s: %w", step, err)
+		}
+		done += index + 1
+	}
+}
+
+// queryParts runs deepQueryScript in the frame and returns the element (if any), the
+// status and the index of the last part it handled
+func (f *frameHandle) queryParts(parts []string) (runtime.RemoteObjectID, string, int, error) {
+	encoded, err := json.Marshal(parts)
+	if err != nil {
+		return "", "", 0, err
+	}
+	list, err := f.evaluate(fmt.Sprintf(deepQueryScript, encoded), false)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	var element runtime.RemoteObjectID
+	var status string
+	var index int
+	err = f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		defer func() { _ = runtime.ReleaseObject(list.ObjectID).Do(ctx) }()
+		props, _, _, _, err := runtime.GetProperties(list.ObjectID).WithOwnProperties(true).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, prop := range props {
+			if prop.Value == nil {
+				continue
+			}
+			switch prop.Name {
+			case "0":
+				element = prop.Value.ObjectID
+			case "1":
+				err = json.Unmarshal(prop.Value.Value, &status)
+			case "2":
+				err = json.Unmarshal(prop.Value.Value, &index)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return element, status, index, err
+}
+
+// childFrame returns the frame rendered by an iframe element of this frame
+func (f *frameHandle) childFrame(owner runtime.RemoteObjectID) (*frameHandle, error) {
+	var node *cdp.Node
+	err := f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		node, err = dom.DescribeNode().WithObjectID(owner).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if node.FrameID == "" {
+		return nil, errors.New("iframe has no content frame")
+	}
+
+	tree, err := getFrameTree(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if child := frameInTree(tree, node.FrameID); child != nil {
+		return &frameHandle{ctx: f.ctx, id: child.ID, name: child.Name, url: child.URL, parent: f}, nil
+	}
+
+	// Not in this target's tree: the iframe is out of process
+	frameCtx, err := oopifContext(f.ctx, target.ID(node.FrameID))
+	if err != nil {
+		return nil, err
+	}
+	subtree, err := getFrameTree(frameCtx)
+	if err != nil {
+		return nil, err
+	}
+	return &frameHandle{ctx: frameCtx, id: node.FrameID, name: subtree.Frame.Name, url: subtree.Frame.URL, parent: f, targetRoot: true}, nil
+}
+
+// frameInTree returns the frame with the given ID in a frame tree
+func frameInTree(tree *page.FrameTree, id cdp.FrameID) *cdp.Frame {
+	if tree.Frame.ID == id {
+		return tree.Frame
+	}
+	for _, child := range tree.ChildFrames {
+		if frame := frameInTree(child, id); frame != nil {
+			return frame
+		}
+	}
+	return nil
+}
+
+// waitSelector resolves a (deep) selector from the frame's document, retrying until it
+// matches or ctx is done
+func (f *frameHandle) waitSelector(ctx context.Context, selector string) (*frameHandle, runtime.RemoteObjectID, error) {
+	for {
+		frame, element, err := f.query(selector)
+		if !errors.Is(err, errNoMatch) {
+			return frame, element, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, "", err
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// elementCenter scrolls the element into view and returns its center in the tab's viewport
+func (f *frameHandle) elementCenter(element runtime.RemoteObjectID) (float64, float64, error) {
+	var quads []dom.Quad
+	err := f.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := dom.ScrollIntoViewIfNeeded().WithObjectID(element).Do(ctx); err != nil {
+			return err
+		}
+		var err error
+		quads, err = dom.GetContentQuads().WithObjectID(element).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(quads) == 0 || len(quads[0]) < 8 {
+		return 0, 0, errors.New("element is not visible")
+	}
+
+	var x, y float64
+	for i := 0; i < 8; i += 2 {
+		x += quads[0][i] / 4
+		y += quads[0][i+1] / 4
+	}
+
+	originX, originY, err := f.targetOrigin()
+	if err != nil {
+		return 0, 0, err
+	}
+	return originX + x, originY + y, nil
+}
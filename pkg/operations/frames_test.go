@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		sep      string
+		want     []string
+	}{
+		{"iframe#pay >>> my-card >>> button", deepSeparator, []string{"iframe#pay", "my-card", "button"}},
+		{"button[title=\">>>\"]", deepSeparator, []string{"button[title=\">>>\"]"}},
+		{"a:is(.x, .y) >>> b", deepSeparator, []string{"a:is(.x, .y)", "b"}},
+		{"a, b[title='c, d'] >>> e", ",", []string{"a", "b[title='c, d'] >>> e"}},
+		{"button", deepSeparator, []string{"button"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitSelector(tt.selector, tt.sep); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitSelector(%q, %q) = %q, want %q", tt.selector, tt.sep, got, tt.want)
+		}
+	}
+
+	if IsDeepSelector("div > span") || !IsDeepSelector("my-app >>> button") {
+		t.Error("IsDeepSelector misclassified a selector")
+	}
+}
+
+func TestQualifyFramePick(t *testing.T) {
+	result := &pickResult{
+		Status: "picked",
+		Elements: []PickedElement{{
+			Selector: "button.pay",
+			XPath:    "//button[text()='Pay']",
+			Candidates: []SelectorCandidate{
+				{Type: "css", Selector: "[data-testid=\"pay\"]", Matches: 1},
+				{Type: "role", Selector: "role=button[name=\"Pay\"]", Matches: 1},
+				{Type: "text", Selector: "text=\"Pay\"", Matches: 1},
+				{Type: "css", Selector: "button.pay", Matches: 1},
+				{Type: "xpath", Selector: "//button[text()='Pay']", Matches: 1},
+			},
+			Box: BoundingBox{X: 10, Y: 20, Width: 50, Height: 30},
+		}},
+		Selector: "button.pay, a.pay",
+	}
+
+	qualifyFramePick(result, "iframe#checkout >>> ", "https://pay.example.com/", 100, 200)
+
+	el := result.Elements[0]
+	if el.Selector != "iframe#checkout >>> button.pay" {
+		t.Errorf("Selector = %q", el.Selector)
+	}
+	if el.XPath != "" {
+		t.Errorf("XPath = %q, want it dropped", el.XPath)
+	}
+	want := []SelectorCandidate{
+		{Type: "css", Selector: "iframe#checkout >>> [data-testid=\"pay\"]", Matches: 1},
+		{Type: "css", Selector: "iframe#checkout >>> button.pay", Matches: 1},
+	}
+	if !reflect.DeepEqual(el.Candidates, want) {
+		t.Errorf("Candidates = %+v, want %+v", el.Candidates, want)
+	}
+	for _, c := range el.Candidates {
+		if parts := splitSelector(c.Selector, deepSeparator); len(parts) != 2 || parts[0] != "iframe#checkout" {
+			t.Errorf("candidate %q doesn't resolve through the iframe", c.Selector)
+		}
+	}
+	if el.Frame != "https://pay.example.com/" || el.Box.X != 110 || el.Box.Y != 220 {
+		t.Errorf("Frame = %q, Box = %+v", el.Frame, el.Box)
+	}
+	if result.Selector != "iframe#checkout >>> button.pay, iframe#checkout >>> a.pay" {
+		t.Errorf("group Selector = %q", result.Selector)
+	}
+}
@@ -0,0 +1,224 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/indexeddb"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// idbPageSize is the number of records requested per IndexedDB.requestData call
+const idbPageSize = 100
+
+// IDBDatabase describes an IndexedDB database and its object stores
+type IDBDatabase struct {
+	Origin  string           `json:"origin"`
+	Name    string           `json:"name"`
+	Version float64          `json:"version"`
+	Stores  []IDBObjectStore `json:"objectStores"`
+}
+
+// IDBObjectStore describes an object store and how many records it holds
+type IDBObjectStore struct {
+	Name          string   `json:"name"`
+	KeyPath       []string `json:"keyPath,omitempty"`
+	AutoIncrement bool     `json:"autoIncrement"`
+	Indexes       []string `json:"indexes,omitempty"`
+	Count         int64    `json:"count"`
+}
+
+// IDBRecord is one object store entry; Key and Value are JSON encoded
+type IDBRecord struct {
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ListIndexedDB lists the IndexedDB databases and object stores of an origin
+// An empty origin uses the current page's origin; the origin must be loaded in the tab
+func ListIndexedDB(ctx context.Context, origin string) ([]IDBDatabase, error) {
+	var databases []IDBDatabase
+
+	err := withIndexedDB(ctx, origin, func(ctx context.Context, origin, key string) error {
+		names, err := indexeddb.RequestDatabaseNames().WithStorageKey(key).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			db, err := indexeddb.RequestDatabase(name).WithStorageKey(key).Do(ctx)
+			if err != nil {
+				return err
+			}
+
+			database := IDBDatabase{Origin: origin, Name: db.Name, Version: db.Version}
+			for _, store := range db.ObjectStores {
+				count, _, err := indexeddb.GetMetadata(name, store.Name).WithStorageKey(key).Do(ctx)
+				if err != nil {
+					return err
+				}
+
+				s := IDBObjectStore{
+					Name:          store.Name,
+					KeyPath:       keyPathStrings(store.KeyPath),
+					AutoIncrement: store.AutoIncrement,
+					Count:         int64(count),
+				}
+				for _, index := range store.Indexes {
+					s.Indexes = append(s.Indexes, index.Name)
+				}
+				database.Stores = append(database.Stores, s)
+			}
+			databases = append(databases, database)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IndexedDB databases: %w", err)
+	}
+
+	return databases, nil
+}
+
+// DumpIndexedDB reads records of an object store in key order and passes each one to fn
+// A limit of 0 reads all records. Values are converted with JSON.stringify, so types
+// without a JSON representation (Map, Set, Blob) come out as {}.
+func DumpIndexedDB(ctx context.Context, origin, database, store string, limit int, fn func(IDBRecord) error) error {
+	err := withIndexedDB(ctx, origin, func(ctx context.Context, _, key string) error {
+		read := 0
+		for {
+			pageSize := idbPageSize
+			if limit > 0 && limit-read < pageSize {
+				pageSize = limit - read
+			}
+
+			entries, hasMore, err := indexeddb.RequestData(database, store, "", int64(read), int64(pageSize)).
+				WithStorageKey(key).
+				Do(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				record := IDBRecord{}
+				if record.Key, err = remoteJSON(ctx, entry.PrimaryKey); err != nil {
+					return err
+				}
+				if record.Value, err = remoteJSON(ctx, entry.Value); err != nil {
+					return err
+				}
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+
+			read += len(entries)
+			if !hasMore || len(entries) == 0 || (limit > 0 && read >= limit) {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read %s/%s: %w", database, store, err)
+	}
+
+	return nil
+}
+
+// ClearIndexedDB clears IndexedDB data of an origin
+// With a store, only that object store is emptied; with only a database, the database is
+// deleted; with neither, every database of the origin is deleted
+func ClearIndexedDB(ctx context.Context, origin, database, store string) error {
+	err := withIndexedDB(ctx, origin, func(ctx context.Context, _, key string) error {
+		if store != "" {
+			return indexeddb.ClearObjectStore(database, store).WithStorageKey(key).Do(ctx)
+		}
+		if database != "" {
+			return indexeddb.DeleteDatabase(database).WithStorageKey(key).Do(ctx)
+		}
+
+		names, err := indexeddb.RequestDatabaseNames().WithStorageKey(key).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := indexeddb.DeleteDatabase(name).WithStorageKey(key).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear IndexedDB: %w", err)
+	}
+
+	return nil
+}
+
+// withIndexedDB enables the IndexedDB domain and runs fn with the origin's storage key
+func withIndexedDB(ctx context.Context, origin string, fn func(ctx context.Context, origin, key string) error) error {
+	return chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		origin, key, err := originStorageKey(ctx, origin)
+		if err != nil {
+			return err
+		}
+
+		if err := indexeddb.Enable().Do(ctx); err != nil {
+			return err
+		}
+		return fn(ctx, origin, string(key))
+	}))
+}
+
+// remoteJSON converts a remote object to JSON and releases it
+func remoteJSON(ctx context.Context, obj *runtime.RemoteObject) (json.RawMessage, error) {
+	if obj == nil {
+		return json.RawMessage("null"), nil
+	}
+	if obj.ObjectID == "" {
+		if obj.UnserializableValue != "" {
+			return json.Marshal(string(obj.UnserializableValue))
+		}
+		if len(obj.Value) == 0 {
+			return json.RawMessage("null"), nil
+		}
+		return json.RawMessage(obj.Value), nil
+	}
+	defer func() { _ = runtime.ReleaseObject(obj.ObjectID).Do(ctx) }()
+
+	result, exception, err := runtime.CallFunctionOn(`function() { return JSON.stringify(this) ?? null; }`).
+		WithObjectID(obj.ObjectID).
+		WithReturnByValue(true).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exception != nil {
+		return nil, exception
+	}
+
+	var encoded *string
+	if err := json.Unmarshal(result.Value, &encoded); err != nil {
+		return nil, err
+	}
+	if encoded == nil {
+		return json.RawMessage("null"), nil
+	}
+	return json.RawMessage(*encoded), nil
+}
+
+// keyPathStrings flattens an object store key path
+func keyPathStrings(keyPath *indexeddb.KeyPath) []string {
+	if keyPath == nil {
+		return nil
+	}
+	switch keyPath.Type {
+	case indexeddb.KeyPathTypeString:
+		return []string{keyPath.String}
+	case indexeddb.KeyPathTypeArray:
+		return keyPath.Array
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// AddInitScript registers JavaScript that runs in every new document of the page, including
+// iframes rendered in the tab's process, before any of the page's own scripts. Out-of-process
+// iframes (Frame.OutOfProcess) don't run it. It returns an identifier for RemoveInitScript.
+// Chrome drops the script when this connection closes, so it only affects navigations made
+// while the connection is open.
+func AddInitScript(ctx context.Context, source string) (string, error) {
+	var id page.ScriptIdentifier
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		id, err = page.AddScriptToEvaluateOnNewDocument(source).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to add init script: %w", err)
+	}
+	return string(id), nil
+}
+
+// RemoveInitScript unregisters a script added with AddInitScript; documents it already ran in
+// are not affected
+func RemoveInitScript(ctx context.Context, id string) error {
+	if err := chromedp.Run(ctx, page.RemoveScriptToEvaluateOnNewDocument(page.ScriptIdentifier(id))); err != nil {
+		return fmt.Errorf("failed to remove init script: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// Click waits for the element matching the CSS selector to be visible and clicks it
+// Selectors may step into iframes and shadow roots with " >>> ".
+func Click(ctx context.Context, selector string) error {
+	if IsDeepSelector(selector) {
+		if err := clickDeep(ctx, "", selector); err != nil {
+			return fmt.Errorf("failed to click %s: %w", selector, err)
+		}
+		return nil
+	}
+
+	if err := chromedp.Run(ctx, chromedp.Click(selector, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to click %s: %w", selector, err)
+	}
+	return nil
+}
+
+// Type focuses the element matching the CSS selector and types text into it
+// Selectors may step into iframes and shadow roots with " >>> ".
+func Type(ctx context.Context, selector, text string) error {
+	if IsDeepSelector(selector) {
+		if err := typeDeep(ctx, "", selector, text); err != nil {
+			return fmt.Errorf("failed to type into %s: %w", selector, err)
+		}
+		return nil
+	}
+
+	if err := chromedp.Run(ctx, chromedp.SendKeys(selector, text, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to type into %s: %w", selector, err)
+	}
+	return nil
+}
+
+// ClickInFrame clicks the element matching the selector inside a frame chosen by name, URL
+// substring or iframe selector (see ListFrames); out-of-process iframes are supported
+func ClickInFrame(ctx context.Context, frame, selector string) error {
+	if err := clickDeep(ctx, frame, selector); err != nil {
+		return fmt.Errorf("failed to click %s in frame %s: %w", selector, frame, err)
+	}
+	return nil
+}
+
+// TypeInFrame types text into the element matching the selector inside a frame
+func TypeInFrame(ctx context.Context, frame, selector, text string) error {
+	if err := typeDeep(ctx, frame, selector, text); err != nil {
+		return fmt.Errorf("failed to type into %s in frame %s: %w", selector, frame, err)
+	}
+	return nil
+}
+
+// clickDeep clicks the center of an element with real mouse events, which Chrome routes to
+// the right frame even when it is out of process
+func clickDeep(ctx context.Context, frameSpec, selector string) error {
+	start, err := resolveFrame(ctx, frameSpec)
+	if err != nil {
+		return err
+	}
+	frame, element, err := start.waitSelector(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	x, y, err := frame.elementCenter(element)
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(ctx, chromedp.MouseClickXY(x, y))
+}
+
+// typeDeep focuses an element and types into the focused frame
+func typeDeep(ctx context.Context, frameSpec, selector, text string) error {
+	start, err := resolveFrame(ctx, frameSpec)
+	if err != nil {
+		return err
+	}
+	frame, element, err := start.waitSelector(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	err = frame.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		_, exception, err := runtime.CallFunctionOn(`function() { this.focus(); }`).WithObjectID(element).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(ctx, chromedp.KeyEvent(text))
+}
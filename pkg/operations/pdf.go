@@ -3,29 +3,159 @@ package operations
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 )
 
+// PaperSize is a paper size in inches
+type PaperSize struct {
+	Width  float64
+	Height float64
+}
+
+// Common paper sizes
+var (
+	PaperLetter  = PaperSize{Width: 8.5, Height: 11}
+	PaperLegal   = PaperSize{Width: 8.5, Height: 14}
+	PaperTabloid = PaperSize{Width: 11, Height: 17}
+	PaperA3      = PaperSize{Width: 11.69, Height: 16.54}
+	PaperA4      = PaperSize{Width: 8.27, Height: 11.69}
+	PaperA5      = PaperSize{Width: 5.83, Height: 8.27}
+)
+
+// paperSizes maps preset names (lowercase) to paper sizes
+var paperSizes = map[string]PaperSize{
+	"letter":  PaperLetter,
+	"legal":   PaperLegal,
+	"tabloid": PaperTabloid,
+	"a3":      PaperA3,
+	"a4":      PaperA4,
+	"a5":      PaperA5,
+}
+
+// PageNumberFooter is a footer template showing "current / total" page numbers
+const PageNumberFooter = `<div style="font-size: 9px; width: 100%; text-align: center;">` +
+	`<span class="pageNumber"></span> / <span class="totalPages"></span></div>`
+
 // PDFOptions configures PDF generation
 type PDFOptions struct {
 	// Landscape orientation (default false)
 	Landscape bool
 	// PrintBackground includes background graphics (default true)
 	PrintBackground bool
+
+	// Paper size in inches (zero uses Chrome's default, US Letter)
+	Paper PaperSize
+	// Margins in inches (default 0)
+	MarginTop    float64
+	MarginBottom float64
+	MarginLeft   float64
+	MarginRight  float64
+	// Scale of the page rendering, from 0.1 to 2 (0 uses the default of 1)
+	Scale float64
+	// PageRanges to print, one based, e.g. "1-5, 8, 11-13" (empty prints all pages)
+	PageRanges string
+	// PreferCSSPageSize uses the page size defined by CSS @page rules over Paper
+	PreferCSSPageSize bool
+
+	// HeaderTemplate and FooterTemplate are HTML printed on every page. Elements with the classes
+	// date, title, url, pageNumber and totalPages are filled in by Chrome. Setting either one
+	// enables headers and footers; leave room for them with MarginTop/MarginBottom.
+	HeaderTemplate string
+	FooterTemplate string
+
+	// Tagged generates a tagged (accessible) PDF
+	Tagged bool
+	// Outline embeds a document outline built from the page headings
+	Outline bool
+}
+
+// PaperSizeByName returns the paper size for a preset name such as "A4" or "Letter"
+func PaperSizeByName(name string) (PaperSize, error) {
+	size, ok := paperSizes[strings.ToLower(name)]
+	if !ok {
+		return PaperSize{}, fmt.Errorf("unknown paper size %q (expected Letter, Legal, Tabloid, A3, A4 or A5)", name)
+	}
+	return size, nil
+}
+
+// ParseLength parses a length such as "10mm", "2.5cm", "1in" or "96px" into inches
+// A bare number is interpreted as inches
+func ParseLength(s string) (float64, error) {
+	input := s
+	s = strings.TrimSpace(strings.ToLower(s))
+
+	units := []struct {
+		suffix string
+		perIn  float64
+	}{
+		{"mm", 25.4},
+		{"cm", 2.54},
+		{"in", 1},
+		{"px", 96},
+		{"pt", 72},
+	}
+
+	divisor := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSuffix(s, u.suffix)
+			divisor = u.perIn
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid length %q (expected e.g. 10mm, 2cm, 1in, 96px or 72pt)", input)
+	}
+
+	return value / divisor, nil
 }
 
 // GeneratePDF generates a PDF from the current page
 func GeneratePDF(ctx context.Context, opts PDFOptions) ([]byte, error) {
-	var buf []byte
+	if opts.Scale != 0 && (opts.Scale < 0.1 || opts.Scale > 2) {
+		return nil, fmt.Errorf("scale must be between 0.1 and 2, got %g", opts.Scale)
+	}
+
+	params := page.PrintToPDF().
+		WithPrintBackground(opts.PrintBackground).
+		WithLandscape(opts.Landscape).
+		WithPaperWidth(opts.Paper.Width).
+		WithPaperHeight(opts.Paper.Height).
+		WithMarginTop(opts.MarginTop).
+		WithMarginBottom(opts.MarginBottom).
+		WithMarginLeft(opts.MarginLeft).
+		WithMarginRight(opts.MarginRight).
+		WithScale(opts.Scale).
+		WithPageRanges(opts.PageRanges).
+		WithPreferCSSPageSize(opts.PreferCSSPageSize).
+		WithGenerateTaggedPDF(opts.Tagged).
+		WithGenerateDocumentOutline(opts.Outline)
 
+	if opts.HeaderTemplate != "" || opts.FooterTemplate != "" {
+		// An empty template makes Chrome print its default (date, title, url), so blank out the unset one
+		header, footer := opts.HeaderTemplate, opts.FooterTemplate
+		if header == "" {
+			header = "<span></span>"
+		}
+		if footer == "" {
+			footer = "<span></span>"
+		}
+		params = params.
+			WithDisplayHeaderFooter(true).
+			WithHeaderTemplate(header).
+			WithFooterTemplate(footer)
+	}
+
+	var buf []byte
 	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
 		var err error
-		buf, _, err = page.PrintToPDF().
-			WithPrintBackground(opts.PrintBackground).
-			WithLandscape(opts.Landscape).
-			Do(ctx)
+		buf, _, err = params.Do(ctx)
 		return err
 	})); err != nil {
 		return nil, fmt.Errorf("failed to generate PDF: %w", err)
@@ -2,136 +2,497 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 )
 
-// InjectPicker injects an interactive element picker into the page
-// If useXPath is true, the picker will return XPath selectors instead of CSS selectors
-func InjectPicker(ctx context.Context, useXPath bool) error {
-	pickerScript := fmt.Sprintf(`
-(function() {
-	if (window.__browPicker) {
-		console.log('Picker already active');
-		return;
-	}
+// pickBinding is the Runtime binding the picker calls with its result
+const pickBinding = "__browPickBinding"
+
+// maxHighlights caps the number of similar elements outlined by the picker
+const maxHighlights = 500
+
+var (
+	// ErrPickCancelled is returned by PickElement when the user presses ESC
+	ErrPickCancelled = errors.New("picker cancelled")
+	// ErrPickTimeout is returned by PickElement when nothing is picked in time
+	ErrPickTimeout = errors.New("timed out waiting for an element to be picked")
+)
+
+// PickOptions configures the element picker
+type PickOptions struct {
+	// XPath makes Selector an XPath instead of a CSS selector, except for elements inside
+	// iframes, which XPath can't reach from the top document
+	XPath bool
+	// Timeout to wait for a pick (0 waits until ctx is done)
+	Timeout time.Duration
+	// Multiple lets the user click several elements (clicking again deselects) and
+	// finish with Enter; a generalized selector matching all of them is computed
+	Multiple bool
+	// Similar highlights all elements similar to the picked ones while picking, so one
+	// click on a list item shows the whole list; implies Multiple
+	Similar bool
+}
+
+// PickedElement describes the element the user clicked
+type PickedElement struct {
+	Selector string `json:"selector"`
+	// XPath is empty for elements inside iframes
+	XPath string `json:"xpath,omitempty"`
+	// Candidates lists alternative selectors, unique ones first; elements inside iframes
+	// only get CSS candidates, qualified with the iframe's " >>> " steps
+	Candidates []SelectorCandidate `json:"candidates"`
+	Tag        string              `json:"tag"`
+	Text       string              `json:"text"`
+	Attributes map[string]string   `json:"attributes"`
+	// Box is relative to the tab's viewport at the time of the click
+	Box BoundingBox `json:"box"`
+	// Frame is the URL of the iframe holding the element (empty for the top document)
+	Frame string `json:"frame,omitempty"`
+}
+
+// PickedGroup is the result of a multi-element pick
+type PickedGroup struct {
+	Elements []PickedElement `json:"elements"`
+	// Selector is a CSS selector matching all picked elements and similar ones
+	Selector string `json:"selector"`
+	// Matches is the number of elements Selector matches in the document of the picked
+	// elements, which is the iframe's document for elements inside an iframe
+	Matches int `json:"matches"`
+}
+
+// pickResult is the payload the picker sends through the binding
+type pickResult struct {
+	Status   string          `json:"status"`
+	Elements []PickedElement `json:"elements"`
+	Selector string          `json:"selector"`
+	Matches  int             `json:"matches"`
+}
+
+// pickerConfig is passed to pickerScript as JSON
+type pickerConfig struct {
+	UseXPath      bool   `json:"useXPath"`
+	Binding       string `json:"binding"`
+	Multiple      bool   `json:"multiple"`
+	Similar       bool   `json:"similar"`
+	MaxHighlights int    `json:"maxHighlights"`
+	// Top is set in the main frame, which shows the instructions before anything is picked
+	Top bool `json:"top"`
+}
+
+// pickerScript highlights elements on hover; a click reports the element and ESC cancels.
+// In multiple mode clicks toggle elements and Enter reports them with a generalized selector.
+// Results are stored in window.__browPickedSelector and sent to the binding if present.
+// Events from inside shadow roots are resolved to the innermost element through composedPath.
+const pickerScript = `
+(function(config) {
+	if (window.__browPickerCleanup) window.__browPickerCleanup();
 
 	window.__browPicker = true;
-	let overlay = null;
-	let selectedElement = null;
-
-	// Get CSS selector for an element
-	function getCSSSelector(el) {
-		if (el.id) return '#' + el.id;
-
-		let path = [];
-		while (el.parentElement) {
-			let selector = el.tagName.toLowerCase();
-			if (el.className) {
-				selector += '.' + Array.from(el.classList).join('.');
-			}
 
-			let siblings = Array.from(el.parentElement.children).filter(
-				e => e.tagName === el.tagName
-			);
-			if (siblings.length > 1) {
-				let index = siblings.indexOf(el) + 1;
-				selector += ':nth-of-type(' + index + ')';
-			}
+	%s
 
-			path.unshift(selector);
-			el = el.parentElement;
-		}
-		return path.join(' > ');
+	const body = document.body || document.documentElement;
+
+	function eventTarget(e) {
+		const inner = e.composedPath()[0];
+		return inner instanceof Element ? inner : e.target;
 	}
 
-	// Get XPath for an element
-	function getXPath(el) {
-		if (el.id) return '//*[@id="' + el.id + '"]';
+	function describe(el) {
+		const rect = el.getBoundingClientRect();
+		const attributes = {};
+		for (const attr of el.attributes) attributes[attr.name] = attr.value;
+		return {
+			selector: config.useXPath ? getXPath(el) : getCSSSelector(el),
+			xpath: getXPath(el),
+			candidates: getSelectorCandidates(el),
+			tag: el.tagName.toLowerCase(),
+			text: normalizeText(el.innerText || el.textContent).slice(0, 200),
+			attributes: attributes,
+			box: { x: rect.x, y: rect.y, width: rect.width, height: rect.height },
+		};
+	}
 
-		let path = [];
-		while (el.parentElement) {
-			let siblings = Array.from(el.parentElement.children).filter(
-				e => e.tagName === el.tagName
-			);
-			let index = siblings.indexOf(el) + 1;
-			path.unshift(el.tagName.toLowerCase() + '[' + index + ']');
-			el = el.parentElement;
+	function report(result) {
+		if (typeof window[config.binding] === 'function') {
+			window[config.binding](JSON.stringify(result));
 		}
-		return '/' + path.join('/');
 	}
 
 	// Create overlay
-	overlay = document.createElement('div');
+	const overlay = document.createElement('div');
 	overlay.style.cssText = 'position: absolute; border: 2px solid red; pointer-events: none; z-index: 999999; background: rgba(255, 0, 0, 0.1);';
-	document.body.appendChild(overlay);
+	body.appendChild(overlay);
+
+	// Outlines of picked (green) and similar (blue) elements in multiple mode
+	const highlights = document.createElement('div');
+	highlights.style.cssText = 'position: absolute; top: 0; left: 0; pointer-events: none; z-index: 999998;';
+	body.appendChild(highlights);
 
 	// Info box
-	let infoBox = document.createElement('div');
-	infoBox.style.cssText = 'position: fixed; top: 10px; right: 10px; background: black; color: white; padding: 10px; z-index: 1000000; font-family: monospace; font-size: 12px;';
-	infoBox.textContent = 'Hover to highlight, Click to select, ESC to exit';
-	document.body.appendChild(infoBox);
+	const infoBox = document.createElement('div');
+	infoBox.style.cssText = 'position: fixed; top: 10px; right: 10px; max-width: 50%%; background: black; color: white; padding: 10px; z-index: 1000000; font-family: monospace; font-size: 12px; white-space: pre-wrap;';
+	infoBox.textContent = config.multiple
+		? 'Click elements to select (again to deselect), Enter to finish, ESC to exit'
+		: 'Hover to highlight, Click to select, ESC to exit';
+	if (config.top) body.appendChild(infoBox);
+
+	const picked = [];
+	let generalized = null;
+
+	function outline(el, style) {
+		const rect = el.getBoundingClientRect();
+		const box = document.createElement('div');
+		box.style.cssText = 'position: absolute; box-sizing: border-box; ' + style +
+			'left: ' + (rect.left + window.scrollX) + 'px; top: ' + (rect.top + window.scrollY) + 'px;' +
+			'width: ' + rect.width + 'px; height: ' + rect.height + 'px;';
+		highlights.appendChild(box);
+	}
+
+	function update() {
+		highlights.replaceChildren();
+		generalized = null;
+		if (picked.length > 0 && (config.similar || picked.length > 1)) {
+			generalized = generalizeSelector(picked);
+			let shown = 0;
+			for (const el of cssMatches(generalized.selector)) {
+				if (picked.includes(el)) continue;
+				if (++shown > config.maxHighlights) break;
+				outline(el, 'border: 2px dashed #0066ff; background: rgba(0, 102, 255, 0.08);');
+			}
+		}
+		for (const el of picked) {
+			outline(el, 'border: 2px solid #00aa44; background: rgba(0, 170, 68, 0.15);');
+		}
+
+		const selector = generalized ? generalized.selector : (picked.length ? getCSSSelector(picked[0]) : '');
+		const matches = generalized ? generalized.matches : picked.length;
+		infoBox.textContent = picked.length + ' picked, ' + matches + ' matching\n' + selector +
+			'\nEnter to finish, ESC to exit';
+		if (!infoBox.isConnected && picked.length > 0) body.appendChild(infoBox);
+	}
+
+	function finish() {
+		const selector = generalized ? generalized.selector : getCSSSelector(picked[0]);
+		const result = {
+			status: 'picked',
+			elements: picked.map(describe),
+			selector: selector,
+			matches: generalized ? generalized.matches : cssMatches(selector).length,
+		};
+		window.__browPickedSelector = config.multiple ? result.selector : result.elements[0].selector;
+
+		cleanup();
+		report(result);
+	}
 
-	// Mouse move handler
 	function handleMouseMove(e) {
-		if (e.target === overlay || e.target === infoBox) return;
+		const target = eventTarget(e);
+		if (target === overlay || target === infoBox) return;
 
-		let rect = e.target.getBoundingClientRect();
+		const rect = target.getBoundingClientRect();
 		overlay.style.left = (rect.left + window.scrollX) + 'px';
 		overlay.style.top = (rect.top + window.scrollY) + 'px';
 		overlay.style.width = rect.width + 'px';
 		overlay.style.height = rect.height + 'px';
 	}
 
-	// Click handler
 	function handleClick(e) {
+		const target = eventTarget(e);
+		if (target === infoBox) return;
 		e.preventDefault();
 		e.stopPropagation();
 
-		selectedElement = e.target;
-		let selector = %s;
-
-		// Store in window for retrieval
-		window.__browPickedSelector = selector;
+		if (!config.multiple) {
+			picked.push(target);
+			finish();
+			return;
+		}
 
-		cleanup();
+		const index = picked.indexOf(target);
+		if (index >= 0) {
+			picked.splice(index, 1);
+		} else {
+			picked.push(target);
+		}
+		update();
 	}
 
-	// ESC handler
 	function handleKeyDown(e) {
 		if (e.key === 'Escape') {
 			cleanup();
+			report({ status: 'cancelled' });
+		} else if (e.key === 'Enter' && config.multiple && picked.length > 0) {
+			e.preventDefault();
+			e.stopPropagation();
+			finish();
 		}
 	}
 
-	// Cleanup function
 	function cleanup() {
 		document.removeEventListener('mousemove', handleMouseMove);
 		document.removeEventListener('click', handleClick, true);
-		document.removeEventListener('keydown', handleKeyDown);
-		if (overlay) overlay.remove();
-		if (infoBox) infoBox.remove();
+		document.removeEventListener('keydown', handleKeyDown, true);
+		overlay.remove();
+		highlights.remove();
+		infoBox.remove();
 		window.__browPicker = false;
+		delete window.__browPickerCleanup;
 	}
 
-	// Attach event listeners
+	window.__browPickerCleanup = cleanup;
 	document.addEventListener('mousemove', handleMouseMove);
 	document.addEventListener('click', handleClick, true);
-	document.addEventListener('keydown', handleKeyDown);
-})();
-`, getPickerFunction(useXPath))
+	document.addEventListener('keydown', handleKeyDown, true);
+})(%s);
+`
+
+// InjectPicker injects an interactive element picker into the page and returns immediately
+// If useXPath is true, the picker will return XPath selectors instead of CSS selectors.
+// The picked selector can be read later with GetPickedSelector; use PickElement to wait for it.
+func InjectPicker(ctx context.Context, useXPath bool) error {
+	return InjectPickerWithOptions(ctx, PickOptions{XPath: useXPath})
+}
+
+// InjectPickerWithOptions injects the picker like InjectPicker with multiple or similar mode
+// In multiple mode GetPickedSelector returns the generalized selector once Enter is pressed.
+// The picker runs in the top document only; PickElement and PickElements cover all frames.
+func InjectPickerWithOptions(ctx context.Context, opts PickOptions) error {
+	script, err := pickerSource(opts, true)
+	if err != nil {
+		return err
+	}
 
-	if err := chromedp.Run(ctx, chromedp.Evaluate(pickerScript, nil)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, nil)); err != nil {
 		return fmt.Errorf("failed to inject picker: %w", err)
 	}
 
 	return nil
 }
 
+// pickerSource returns the picker script for the given options
+func pickerSource(opts PickOptions, top bool) (string, error) {
+	config, err := json.Marshal(pickerConfig{
+		UseXPath:      opts.XPath,
+		Binding:       pickBinding,
+		Multiple:      opts.Multiple || opts.Similar,
+		Similar:       opts.Similar,
+		MaxHighlights: maxHighlights,
+		Top:           top,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode picker options: %w", err)
+	}
+
+	return fmt.Sprintf(pickerScript, selectorHelpersJS, config), nil
+}
+
+// PickElement injects the picker and blocks until the user clicks an element, presses ESC
+// (ErrPickCancelled) or the timeout expires (ErrPickTimeout)
+// The picker runs in every frame, including out-of-process iframes; selectors of elements in
+// iframes and shadow roots use " >>> " steps, which Click and Type resolve.
+// The result is delivered through a Runtime binding, so nothing is polled.
+func PickElement(ctx context.Context, opts PickOptions) (*PickedElement, error) {
+	opts.Multiple, opts.Similar = false, false
+
+	result, err := runPicker(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Elements[0], nil
+}
+
+// PickElements injects the picker in multiple mode and blocks until the user presses Enter,
+// returning the picked elements and a generalized selector that matches all of them
+// Elements are picked within one frame: the one where Enter is pressed.
+func PickElements(ctx context.Context, opts PickOptions) (*PickedGroup, error) {
+	opts.Multiple = true
+
+	result, err := runPicker(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PickedGroup{
+		Elements: result.Elements,
+		Selector: result.Selector,
+		Matches:  result.Matches,
+	}, nil
+}
+
+// framePick is a binding call from the picker in one frame
+type framePick struct {
+	frame   *frameHandle
+	payload string
+}
+
+// runPicker injects the picker into brow's isolated world of every frame and waits for the
+// first result through the binding
+func runPicker(ctx context.Context, opts PickOptions) (*pickResult, error) {
+	frames, err := tabFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the frames by target and resolve their isolated worlds, which identify the
+	// frame a binding call comes from
+	type targetFrames struct {
+		ctx    context.Context
+		worlds map[runtime.ExecutionContextID]*frameHandle
+	}
+	var targets []*targetFrames
+	byContext := map[context.Context]*targetFrames{}
+	for _, f := range frames {
+		t := byContext[f.ctx]
+		if t == nil {
+			t = &targetFrames{ctx: f.ctx, worlds: map[runtime.ExecutionContextID]*frameHandle{}}
+			byContext[f.ctx] = t
+			targets = append(targets, t)
+		}
+		world, err := f.isolatedWorld()
+		if err != nil {
+			if f.parent == nil {
+				return nil, err
+			}
+			continue
+		}
+		t.worlds[world] = f
+	}
+
+	picks := make(chan framePick, 1)
+	for _, t := range targets {
+		worlds := t.worlds
+		listenCtx, cancel := context.WithCancel(t.ctx)
+		defer cancel()
+		chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+			e, ok := ev.(*runtime.EventBindingCalled)
+			if !ok || e.Name != pickBinding || worlds[e.ExecutionContextID] == nil {
+				return
+			}
+			select {
+			case picks <- framePick{frame: worlds[e.ExecutionContextID], payload: e.Payload}:
+			default:
+			}
+		})
+
+		// The binding is exposed to brow's isolated worlds only, not to page scripts
+		if err := chromedp.Run(t.ctx, runtime.AddBinding(pickBinding).WithExecutionContextName(isolatedWorldName)); err != nil {
+			return nil, fmt.Errorf("failed to register picker binding: %w", err)
+		}
+		defer func(ctx context.Context) { _ = chromedp.Run(ctx, runtime.RemoveBinding(pickBinding)) }(t.ctx)
+	}
+
+	// XPath is applied once the frame of the pick is known (see qualifyPick)
+	frameOpts := opts
+	frameOpts.XPath = false
+	for _, f := range frames {
+		script, err := pickerSource(frameOpts, f.parent == nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.evaluate(script, true); err != nil && f.parent == nil {
+			return nil, fmt.Errorf("failed to inject picker: %w", err)
+		}
+	}
+	defer func() {
+		for _, f := range frames {
+			_, _ = f.evaluate(`window.__browPickerCleanup && window.__browPickerCleanup()`, true)
+		}
+	}()
+
+	var timeout <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case pick := <-picks:
+		var result pickResult
+		if err := json.Unmarshal([]byte(pick.payload), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse picked element: %w", err)
+		}
+		if result.Status != "picked" || len(result.Elements) == 0 {
+			return nil, ErrPickCancelled
+		}
+		if err := qualifyPick(pick.frame, &result, opts.XPath); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	case <-timeout:
+		return nil, ErrPickTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// qualifyPick makes the selectors of a pick resolvable from the top document and moves boxes
+// into the tab's viewport. Elements in the top document get XPath selectors if xpath is set.
+// For a pick in a child frame, only CSS selectors survive, prefixed with the iframe's steps:
+// the deep resolver and document.evaluate can't follow XPath, text or role selectors there.
+func qualifyPick(frame *frameHandle, result *pickResult, xpath bool) error {
+	if frame.parent == nil {
+		if xpath {
+			for i := range result.Elements {
+				result.Elements[i].Selector = result.Elements[i].XPath
+			}
+		}
+		return nil
+	}
+
+	prefix, err := frame.selectorPrefix()
+	if err != nil {
+		return err
+	}
+	originX, originY, err := frame.contentOrigin()
+	if err != nil {
+		return err
+	}
+	qualifyFramePick(result, prefix, frame.url, originX, originY)
+	return nil
+}
+
+// qualifyFramePick prefixes the CSS selectors of a pick in an iframe and drops the others
+func qualifyFramePick(result *pickResult, prefix, frameURL string, originX, originY float64) {
+	for i := range result.Elements {
+		el := &result.Elements[i]
+		el.Selector = prefix + el.Selector
+		el.XPath = ""
+		el.Frame = frameURL
+		el.Box.X += originX
+		el.Box.Y += originY
+
+		candidates := el.Candidates[:0]
+		for _, c := range el.Candidates {
+			if c.Type == "css" {
+				c.Selector = prefix + c.Selector
+				candidates = append(candidates, c)
+			}
+		}
+		el.Candidates = candidates
+	}
+
+	// A generalized selector may be a list of selectors
+	if result.Selector == "" {
+		return
+	}
+	parts := splitSelector(result.Selector, ",")
+	for i := range parts {
+		parts[i] = prefix + parts[i]
+	}
+	result.Selector = strings.Join(parts, ", ")
+}
+
 // GetPickedSelector retrieves the selector picked by the user
 func GetPickedSelector(ctx context.Context) (string, error) {
-	result, err := Evaluate(ctx, "window.__browPickedSelector")
+	result, err := EvaluateIsolated(ctx, "window.__browPickedSelector")
 	if err != nil {
 		return "", err
 	}
@@ -142,10 +503,3 @@ func GetPickedSelector(ctx context.Context) (string, error) {
 
 	return "", nil
 }
-
-func getPickerFunction(useXPath bool) string {
-	if useXPath {
-		return "getXPath(selectedElement)"
-	}
-	return "getCSSSelector(selectedElement)"
-}
@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// RenderOptions selects the outputs produced when rendering a document
+// If neither PDF nor Screenshot is set, a PDF with background graphics is generated
+type RenderOptions struct {
+	// PDF generates a PDF with these options
+	PDF *PDFOptions
+	// Screenshot captures an image with these options
+	Screenshot *ScreenshotOptions
+}
+
+// RenderResult holds the outputs of a render
+type RenderResult struct {
+	Title      string
+	PDF        []byte
+	Screenshot []byte
+}
+
+// waitForAssetsScript resolves once web fonts and all images in the document have loaded or failed
+const waitForAssetsScript = `
+(async () => {
+	await document.fonts.ready;
+	await Promise.all(Array.from(document.images)
+		.filter(img => !img.complete)
+		.map(img => new Promise(resolve => {
+			img.addEventListener('load', resolve, { once: true });
+			img.addEventListener('error', resolve, { once: true });
+		})));
+	return true;
+})()
+`
+
+// RenderHTML replaces the page's document with html via Page.setDocumentContent and renders it
+// Relative URLs in html resolve against about:blank, so assets should use absolute or data: URLs
+func RenderHTML(ctx context.Context, html string, opts RenderOptions) (*RenderResult, error) {
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			return page.SetDocumentContent(tree.Frame.ID, html).Do(ctx)
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to load HTML: %w", err)
+	}
+
+	return render(ctx, opts)
+}
+
+// RenderURL navigates to url (for example a file:// URL) and renders it
+func RenderURL(ctx context.Context, url string, opts RenderOptions) (*RenderResult, error) {
+	if _, err := Navigate(ctx, url, true); err != nil {
+		return nil, err
+	}
+
+	return render(ctx, opts)
+}
+
+// render waits for fonts and images, then produces the requested outputs
+func render(ctx context.Context, opts RenderOptions) (*RenderResult, error) {
+	var ready bool
+	if err := chromedp.Run(ctx, chromedp.Evaluate(waitForAssetsScript, &ready, awaitPromise)); err != nil {
+		return nil, fmt.Errorf("failed waiting for fonts and images: %w", err)
+	}
+
+	result := &RenderResult{}
+	if err := chromedp.Run(ctx, chromedp.Title(&result.Title)); err != nil {
+		return nil, fmt.Errorf("failed to get title: %w", err)
+	}
+
+	if opts.PDF == nil && opts.Screenshot == nil {
+		opts.PDF = &PDFOptions{PrintBackground: true}
+	}
+
+	if opts.PDF != nil {
+		buf, err := GeneratePDF(ctx, *opts.PDF)
+		if err != nil {
+			return nil, err
+		}
+		result.PDF = buf
+	}
+
+	if opts.Screenshot != nil {
+		buf, err := CaptureScreenshot(ctx, *opts.Screenshot)
+		if err != nil {
+			return nil, err
+		}
+		result.Screenshot = buf
+	}
+
+	return result, nil
+}
+
+// awaitPromise makes Runtime.evaluate wait for a returned promise to settle
+func awaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
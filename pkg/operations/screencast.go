@@ -0,0 +1,221 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg" // Register JPEG decoder for screencast frames
+	_ "image/png"  // Register PNG decoder for screencast frames
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ScreencastOptions configures screencast recording
+type ScreencastOptions struct {
+	// MaxFPS limits how many frames per second are kept (default 5, 0 uses the default)
+	MaxFPS int
+	// MaxWidth and MaxHeight limit the frame size in pixels (0 means no limit)
+	MaxWidth  int
+	MaxHeight int
+	// Quality is the JPEG compression quality (1-100, default 80)
+	Quality int
+	// OnFrame, if set, receives every kept frame as it arrives instead of buffering it.
+	// It runs on the recorder goroutine; a returned error stops the recording.
+	OnFrame func(ScreencastFrame) error
+}
+
+// ScreencastFrame is a single captured frame
+type ScreencastFrame struct {
+	// Data is the JPEG-encoded image
+	Data []byte
+	// Timestamp is when the browser rendered the frame
+	Timestamp time.Time
+}
+
+// Screencast is a running screencast started with StartScreencast
+type Screencast struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	frames []ScreencastFrame
+	err    error
+}
+
+// StartScreencast starts capturing frames of the page via Page.startScreencast
+// Frames are collected until Stop is called
+func StartScreencast(ctx context.Context, opts ScreencastOptions) (*Screencast, error) {
+	if opts.MaxFPS <= 0 {
+		opts.MaxFPS = 5
+	}
+	if opts.Quality <= 0 {
+		opts.Quality = 80
+	}
+	minInterval := time.Second / time.Duration(opts.MaxFPS)
+
+	// Make sure the target is attached so the listener is registered on it
+	if err := chromedp.Run(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start screencast: %w", err)
+	}
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	sc := &Screencast{
+		ctx:    listenCtx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	// The listener must not block or send CDP commands, so frames are handed to a goroutine
+	events := make(chan *page.EventScreencastFrame, 64)
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		if frame, ok := ev.(*page.EventScreencastFrame); ok {
+			select {
+			case events <- frame:
+			case <-listenCtx.Done():
+			}
+		}
+	})
+
+	go sc.run(events, opts, minInterval)
+
+	params := page.StartScreencast().
+		WithFormat(page.ScreencastFormatJpeg).
+		WithQuality(int64(opts.Quality)).
+		WithEveryNthFrame(1)
+	if opts.MaxWidth > 0 {
+		params = params.WithMaxWidth(int64(opts.MaxWidth))
+	}
+	if opts.MaxHeight > 0 {
+		params = params.WithMaxHeight(int64(opts.MaxHeight))
+	}
+
+	if err := chromedp.Run(ctx, params); err != nil {
+		cancel()
+		<-sc.done
+		return nil, fmt.Errorf("failed to start screencast: %w", err)
+	}
+
+	return sc, nil
+}
+
+// run acknowledges incoming frames and keeps those allowed by the frame rate limit
+func (s *Screencast) run(events <-chan *page.EventScreencastFrame, opts ScreencastOptions, minInterval time.Duration) {
+	defer close(s.done)
+
+	var last time.Time
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case ev := <-events:
+			// Chrome stops sending frames until the previous one is acknowledged
+			if err := chromedp.Run(s.ctx, page.ScreencastFrameAck(ev.SessionID)); err != nil {
+				if s.ctx.Err() == nil {
+					s.fail(fmt.Errorf("failed to acknowledge frame: %w", err))
+				}
+				return
+			}
+
+			ts := time.Now()
+			if ev.Metadata != nil && ev.Metadata.Timestamp != nil {
+				ts = ev.Metadata.Timestamp.Time()
+			}
+			if !last.IsZero() && ts.Sub(last) < minInterval {
+				continue
+			}
+			last = ts
+
+			data, err := base64.StdEncoding.DecodeString(ev.Data)
+			if err != nil {
+				s.fail(fmt.Errorf("failed to decode frame: %w", err))
+				return
+			}
+
+			frame := ScreencastFrame{Data: data, Timestamp: ts}
+			if opts.OnFrame != nil {
+				if err := opts.OnFrame(frame); err != nil {
+					s.fail(err)
+					return
+				}
+				continue
+			}
+
+			s.mu.Lock()
+			s.frames = append(s.frames, frame)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *Screencast) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Stop ends the screencast and returns the buffered frames
+// Frames delivered to OnFrame are not buffered, so the slice is empty in that case
+func (s *Screencast) Stop() ([]ScreencastFrame, error) {
+	stopErr := chromedp.Run(s.ctx, page.StopScreencast())
+	s.cancel()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.frames, s.err
+	}
+	if stopErr != nil {
+		return s.frames, fmt.Errorf("failed to stop screencast: %w", stopErr)
+	}
+	return s.frames, nil
+}
+
+// EncodeGIF encodes frames as an animated GIF, using frame timestamps for timing
+func EncodeGIF(frames []ScreencastFrame) ([]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames to encode")
+	}
+
+	anim := &gif.GIF{}
+	for i, frame := range frames {
+		img, _, err := image.Decode(bytes.NewReader(frame.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode frame %d: %w", i, err)
+		}
+
+		paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, image.Point{})
+
+		// GIF delays are in hundredths of a second; the last frame holds for one second
+		delay := 100
+		if i+1 < len(frames) {
+			delay = int(frames[i+1].Timestamp.Sub(frame.Timestamp) / (10 * time.Millisecond))
+		}
+		if delay < 2 {
+			// Most viewers treat delays below 2 as "as fast as possible"
+			delay = 2
+		}
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
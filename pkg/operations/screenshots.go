@@ -2,8 +2,12 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 )
 
@@ -13,10 +17,19 @@ type ScreenshotOptions struct {
 	FullPage bool
 	// Quality for full-page screenshots (0-100, default 100)
 	Quality int
+	// Frame captures only the iframe chosen by name, URL substring or iframe selector
+	Frame string
 }
 
 // CaptureScreenshot captures a screenshot of the current page
 func CaptureScreenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, error) {
+	if opts.Frame != "" {
+		if opts.FullPage {
+			return nil, fmt.Errorf("full-page screenshots can't be limited to a frame")
+		}
+		return captureFrame(ctx, opts.Frame)
+	}
+
 	var buf []byte
 	var action chromedp.Action
 
@@ -36,3 +49,95 @@ func CaptureScreenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, err
 
 	return buf, nil
 }
+
+// captureFrame scrolls an iframe into view and captures its content box
+func captureFrame(ctx context.Context, spec string) ([]byte, error) {
+	frame, err := resolveFrame(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	if frame.parent == nil {
+		return CaptureScreenshot(ctx, ScreenshotOptions{})
+	}
+
+	var model *dom.BoxModel
+	err = frame.parent.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		backendID, _, err := dom.GetFrameOwner(frame.id).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if err := dom.ScrollIntoViewIfNeeded().WithBackendNodeID(backendID).Do(ctx); err != nil {
+			return err
+		}
+		model, err = dom.GetBoxModel().WithBackendNodeID(backendID).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate frame %s: %w", spec, err)
+	}
+	originX, originY, err := frame.parent.targetOrigin()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// The clip is in document coordinates of the tab
+		_, _, _, layout, _, _, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return err
+		}
+		content := model.Content
+		buf, err = page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormatPng).
+			WithClip(&page.Viewport{
+				X:      math.Round(originX + content[0] + float64(layout.PageX)),
+				Y:      math.Round(originY + content[1] + float64(layout.PageY)),
+				Width:  math.Round(content[4] - content[0]),
+				Height: math.Round(content[5] - content[1]),
+				Scale:  1,
+			}).
+			Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture frame %s: %w", spec, err)
+	}
+
+	return buf, nil
+}
+
+// ElementRegions returns the bounding boxes of all elements matching the CSS selectors,
+// in screenshot pixels (scaled by devicePixelRatio), for masking parts of a screenshot
+func ElementRegions(ctx context.Context, selectors []string, fullPage bool) ([]BoundingBox, error) {
+	selectorsJSON, err := json.Marshal(selectors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to escape selectors: %w", err)
+	}
+
+	script := fmt.Sprintf(`
+		((selectors, fullPage) => {
+			let dpr = window.devicePixelRatio || 1;
+			let boxes = [];
+			for (let sel of selectors) {
+				for (let el of document.querySelectorAll(sel)) {
+					let r = el.getBoundingClientRect();
+					boxes.push({
+						x: (r.left + (fullPage ? scrollX : 0)) * dpr,
+						y: (r.top + (fullPage ? scrollY : 0)) * dpr,
+						width: r.width * dpr,
+						height: r.height * dpr
+					});
+				}
+			}
+			return boxes;
+		})(%s, %t)
+	`, string(selectorsJSON), fullPage)
+
+	var boxes []BoundingBox
+	if err := chromedp.Run(ctx, evaluateIsolated(script, &boxes)); err != nil {
+		return nil, fmt.Errorf("failed to locate elements: %w", err)
+	}
+
+	return boxes, nil
+}
@@ -0,0 +1,426 @@
+package operations
+
+// SelectorCandidate is one way to select a picked element, ranked best first
+type SelectorCandidate struct {
+	// Type is css, xpath, text (Playwright-style text="...") or role (role=button[name="..."])
+	// Click, Type and SetInputFiles take css candidates; text and role are for Playwright
+	Type     string `json:"type"`
+	Selector string `json:"selector"`
+	// Matches is the number of elements the selector matches in the element's document, which
+	// is the iframe's document for elements inside an iframe (1 is unique)
+	Matches int `json:"matches"`
+}
+
+// selectorHelpersJS defines selector generation helpers in the page, shared by the picker and
+// the annotated screenshot so both produce the same selectors:
+//   - getCSSSelector(el): the shortest unique CSS selector, preferring test ids, stable ids,
+//     aria-label and name attributes over structural nth-of-type chains
+//   - getXPath(el): a unique XPath, preferring attributes and exact text over positions
+//   - getSelectorCandidates(el): ranked CSS, XPath, text and role candidates with match counts
+//   - generalizeSelector(elements): a selector matching all elements and similar ones
+//   - implicitRole(el), accessibleName(el) and labelText(el)
+//
+// Selectors for elements inside open shadow roots are prefixed with the host's selector and
+// " >>> ", which cssMatches (and brow's resolver) follow into shadow roots and iframes.
+const selectorHelpersJS = `
+const TEST_ID_ATTRIBUTES = ['data-testid', 'data-test-id', 'data-test', 'data-cy', 'data-qa'];
+const STABLE_ATTRIBUTES = ['aria-label', 'name', 'placeholder', 'title', 'alt', 'for'];
+
+function normalizeText(s) {
+	return String(s || '').replace(/\s+/g, ' ').trim();
+}
+
+// Framework-generated ids and classes (ember123, :r1:, css-1x2y3z) change between builds
+function looksGenerated(value) {
+	return /\d{3,}|^:|^(css|sc|jsx|svelte|emotion)-|[a-z]\d[a-z]\d/i.test(value);
+}
+
+function cssString(s) {
+	return '"' + String(s).replace(/\\/g, '\\\\').replace(/"/g, '\\"') + '"';
+}
+
+function xpathString(s) {
+	if (!s.includes('"')) return '"' + s + '"';
+	if (!s.includes("'")) return "'" + s + "'";
+	return 'concat("' + s.split('"').join('", \'"\', "') + '")';
+}
+
+// splitSelector splits a selector on sep outside quotes, brackets and parentheses
+function splitSelector(selector, sep) {
+	const parts = [];
+	let depth = 0, quote = '', start = 0;
+	for (let i = 0; i < selector.length; i++) {
+		const ch = selector[i];
+		if (quote) {
+			if (ch === '\\') i++;
+			else if (ch === quote) quote = '';
+		} else if (ch === '"' || ch === "'") {
+			quote = ch;
+		} else if (ch === '[' || ch === '(') {
+			depth++;
+		} else if (ch === ']' || ch === ')') {
+			depth--;
+		} else if (depth === 0 && selector.startsWith(sep, i)) {
+			parts.push(selector.slice(start, i).trim());
+			start = i + sep.length;
+			i = start - 1;
+		}
+	}
+	parts.push(selector.slice(start).trim());
+	return parts;
+}
+
+// deepQuerySelectorAll resolves selectors whose " >>> " steps enter the shadow root or the
+// (same-origin) document of the element matched so far, e.g. "my-app >>> button.save"
+function deepQuerySelectorAll(selector, root) {
+	const results = [];
+	for (const single of splitSelector(selector, ',')) {
+		const steps = splitSelector(single, '>>>');
+		let roots = [root || document];
+		for (let i = 0; i < steps.length && roots.length; i++) {
+			const found = [];
+			for (const r of roots) found.push(...r.querySelectorAll(steps[i]));
+			if (i === steps.length - 1) {
+				results.push(...found);
+				break;
+			}
+			roots = found
+				.map(el => el.shadowRoot || (el.tagName === 'IFRAME' || el.tagName === 'FRAME' ? el.contentDocument : null))
+				.filter(Boolean);
+		}
+	}
+	return Array.from(new Set(results));
+}
+
+function cssMatches(selector, root) {
+	try {
+		if (selector.includes('>>>')) return deepQuerySelectorAll(selector, root);
+		return Array.from((root || document).querySelectorAll(selector));
+	} catch (e) {
+		return [];
+	}
+}
+
+// isUniqueCSS checks selector within el's own document or shadow root
+function isUniqueCSS(selector, el) {
+	const matches = cssMatches(selector, el.getRootNode());
+	return matches.length === 1 && matches[0] === el;
+}
+
+// shadowPrefix returns the selector of el's shadow host followed by " >>> ", or '' in the light DOM
+function shadowPrefix(el) {
+	const root = el.getRootNode();
+	return root instanceof ShadowRoot ? getCSSSelector(root.host) + ' >>> ' : '';
+}
+
+function xpathMatches(xpath) {
+	try {
+		const result = document.evaluate(xpath, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+		const nodes = [];
+		for (let i = 0; i < result.snapshotLength; i++) nodes.push(result.snapshotItem(i));
+		return nodes;
+	} catch (e) {
+		return [];
+	}
+}
+
+function isUniqueXPath(xpath, el) {
+	const matches = xpathMatches(xpath);
+	return matches.length === 1 && matches[0] === el;
+}
+
+function implicitRole(el) {
+	const explicit = el.getAttribute('role');
+	if (explicit) return explicit.split(' ')[0];
+	const tag = el.tagName.toLowerCase();
+	if (tag === 'a') return el.hasAttribute('href') ? 'link' : 'generic';
+	if (tag === 'button' || tag === 'summary') return 'button';
+	if (tag === 'select') return el.multiple ? 'listbox' : 'combobox';
+	if (tag === 'textarea') return 'textbox';
+	if (/^h[1-6]$/.test(tag)) return 'heading';
+	if (tag === 'img') return 'img';
+	if (tag === 'li') return 'listitem';
+	if (tag === 'ul' || tag === 'ol') return 'list';
+	if (tag === 'nav') return 'navigation';
+	if (tag === 'dialog') return 'dialog';
+	if (tag === 'option') return 'option';
+	if (tag === 'input') {
+		const type = (el.getAttribute('type') || 'text').toLowerCase();
+		if (type === 'checkbox' || type === 'radio') return type;
+		if (['button', 'submit', 'reset', 'image'].includes(type)) return 'button';
+		if (type === 'range') return 'slider';
+		if (type === 'search') return 'searchbox';
+		return 'textbox';
+	}
+	if (el.isContentEditable) return 'textbox';
+	return 'generic';
+}
+
+// accessibleName approximates the accessible name computation for common cases
+function accessibleName(el) {
+	let name = el.getAttribute('aria-label');
+	if (!name && el.hasAttribute('aria-labelledby')) {
+		name = el.getAttribute('aria-labelledby').split(/\s+/)
+			.map(id => document.getElementById(id))
+			.filter(Boolean)
+			.map(label => label.textContent)
+			.join(' ');
+	}
+	if (!name && el.labels && el.labels.length) name = el.labels[0].textContent;
+	if (!name) name = el.getAttribute('alt') || '';
+	if (!name && implicitRole(el) !== 'textbox') {
+		name = el.tagName === 'INPUT' ? el.value : el.textContent;
+	}
+	if (!name) name = el.getAttribute('title') || el.getAttribute('placeholder') || '';
+	return normalizeText(name).slice(0, 80);
+}
+
+function labelText(el) {
+	const text = el.getAttribute('aria-label') || el.innerText || el.value ||
+		el.getAttribute('placeholder') || el.getAttribute('title') || el.getAttribute('alt') || '';
+	return normalizeText(text).slice(0, 80);
+}
+
+// stepCandidates returns selectors for el on its own, most readable first
+function stepCandidates(el) {
+	const tag = el.tagName.toLowerCase();
+	const steps = [];
+	for (const attr of TEST_ID_ATTRIBUTES) {
+		const value = el.getAttribute(attr);
+		if (value) steps.push('[' + attr + '=' + cssString(value) + ']');
+	}
+	if (el.id && !looksGenerated(el.id)) steps.push('#' + CSS.escape(el.id));
+	for (const attr of STABLE_ATTRIBUTES) {
+		const value = el.getAttribute(attr);
+		if (value && value.length <= 80) steps.push(tag + '[' + attr + '=' + cssString(value) + ']');
+	}
+	const classes = Array.from(el.classList).filter(c => !looksGenerated(c)).map(c => '.' + CSS.escape(c));
+	for (const c of classes.slice(0, 3)) steps.push(tag + c);
+	if (classes.length > 1) steps.push(tag + classes.slice(0, 3).join(''));
+	steps.push(tag);
+	return steps;
+}
+
+function nthStep(el) {
+	const tag = el.tagName.toLowerCase();
+	const parent = el.parentElement;
+	if (!parent) return tag;
+	const same = Array.from(parent.children).filter(e => e.tagName === el.tagName);
+	return same.length > 1 ? tag + ':nth-of-type(' + (same.indexOf(el) + 1) + ')' : tag;
+}
+
+function getCSSSelector(el) {
+	return shadowPrefix(el) + localCSSSelector(el);
+}
+
+// localCSSSelector returns a selector for el that is unique within its document or shadow root
+function localCSSSelector(el) {
+	const own = stepCandidates(el);
+	for (const step of own) {
+		if (isUniqueCSS(step, el)) return step;
+	}
+
+	// A stable ancestor plus the element, e.g. "form#login button[type=submit]"; shortest first
+	const ancestors = [];
+	for (let a = el.parentElement; a && a !== document.documentElement && ancestors.length < 5; a = a.parentElement) {
+		ancestors.push(a);
+	}
+	const ownSteps = own.concat(nthStep(el));
+	const pairs = [];
+	for (const a of ancestors) {
+		for (const anchor of stepCandidates(a).slice(0, 4)) {
+			for (const step of ownSteps) pairs.push(anchor + ' ' + step);
+		}
+	}
+	pairs.sort((x, y) => x.length - y.length);
+	for (const candidate of pairs) {
+		if (isUniqueCSS(candidate, el)) return candidate;
+	}
+
+	// Structural path, anchored at the nearest ancestor that makes it unique
+	const chain = [nthStep(el)];
+	for (let node = el.parentElement; node && node !== document.documentElement; node = node.parentElement) {
+		for (const anchor of stepCandidates(node)) {
+			const candidate = anchor + ' > ' + chain.join(' > ');
+			if (isUniqueCSS(candidate, el)) return candidate;
+		}
+		chain.unshift(nthStep(node));
+	}
+	return chain.join(' > ');
+}
+
+function positionalXPath(el) {
+	const path = [];
+	while (el.parentElement) {
+		const siblings = Array.from(el.parentElement.children).filter(e => e.tagName === el.tagName);
+		path.unshift(el.tagName.toLowerCase() + '[' + (siblings.indexOf(el) + 1) + ']');
+		el = el.parentElement;
+	}
+	path.unshift(el.tagName.toLowerCase());
+	return '/' + path.join('/');
+}
+
+function getXPath(el) {
+	// XPath can't enter shadow roots; use the host's XPath and the position within the root
+	const root = el.getRootNode();
+	if (root instanceof ShadowRoot) return getXPath(root.host) + ' >>> ' + positionalXPath(el);
+
+	const tag = el.tagName.toLowerCase();
+	const candidates = [];
+	for (const attr of TEST_ID_ATTRIBUTES) {
+		const value = el.getAttribute(attr);
+		if (value) candidates.push('//*[@' + attr + '=' + xpathString(value) + ']');
+	}
+	if (el.id && !looksGenerated(el.id)) candidates.push('//*[@id=' + xpathString(el.id) + ']');
+	const text = normalizeText(el.textContent);
+	if (text && text.length <= 50) candidates.push('//' + tag + '[normalize-space()=' + xpathString(text) + ']');
+	for (const attr of ['aria-label', 'name', 'placeholder']) {
+		const value = el.getAttribute(attr);
+		if (value) candidates.push('//' + tag + '[@' + attr + '=' + xpathString(value) + ']');
+	}
+	if (text && text.length > 50) {
+		candidates.push('//' + tag + '[contains(normalize-space(), ' + xpathString(text.slice(0, 40)) + ')]');
+	}
+
+	for (const candidate of candidates) {
+		if (isUniqueXPath(candidate, el)) return candidate;
+	}
+	return positionalXPath(el);
+}
+
+// innermostTextMatches counts the deepest elements whose whole text equals text,
+// mirroring how Playwright's text="..." selector matches
+function innermostTextMatches(text) {
+	let count = 0;
+	for (const e of document.body.querySelectorAll('*')) {
+		if (normalizeText(e.textContent) !== text) continue;
+		if (Array.from(e.children).some(c => normalizeText(c.textContent) === text)) continue;
+		count++;
+	}
+	return count;
+}
+
+function roleMatches(role, name) {
+	let count = 0;
+	for (const e of document.body.querySelectorAll('*')) {
+		if (implicitRole(e) === role && accessibleName(e) === name) count++;
+	}
+	return count;
+}
+
+function getSelectorCandidates(el) {
+	const candidates = [];
+	const seen = new Set();
+	function add(type, selector, matches) {
+		if (!selector || seen.has(type + selector) || matches < 1) return;
+		seen.add(type + selector);
+		candidates.push({ type: type, selector: selector, matches: matches });
+	}
+
+	const prefix = shadowPrefix(el);
+	const root = el.getRootNode();
+	for (const step of stepCandidates(el)) {
+		if (step.startsWith('[') || step.startsWith('#')) add('css', prefix + step, cssMatches(step, root).length);
+	}
+
+	const role = implicitRole(el);
+	const name = accessibleName(el);
+	if (role !== 'generic' && name) {
+		add('role', 'role=' + role + '[name=' + cssString(name) + ']', roleMatches(role, name));
+	}
+
+	const text = normalizeText(el.textContent);
+	if (text && text.length <= 50 && !Array.from(el.children).some(c => normalizeText(c.textContent) === text)) {
+		add('text', 'text=' + cssString(text), innermostTextMatches(text));
+	}
+
+	for (const step of stepCandidates(el)) {
+		if (!step.startsWith('[') && !step.startsWith('#') && step.includes('[')) add('css', prefix + step, cssMatches(step, root).length);
+	}
+
+	const css = getCSSSelector(el);
+	add('css', css, cssMatches(css).length);
+	const xpath = getXPath(el);
+	add('xpath', xpath, xpathMatches(xpath).length);
+
+	// Unique selectors first, keeping the preference order within each group
+	return candidates
+		.map((c, i) => ({ c: c, i: i }))
+		.sort((a, b) => ((a.c.matches === 1 ? 0 : 1) - (b.c.matches === 1 ? 0 : 1)) || a.i - b.i)
+		.map(x => x.c);
+}
+
+// similarSteps returns element-level selectors shared by all elements, most specific first
+function similarSteps(elements) {
+	const first = elements[0];
+	const tag = elements.every(e => e.tagName === first.tagName) ? first.tagName.toLowerCase() : '';
+	const steps = [];
+	for (const attr of TEST_ID_ATTRIBUTES) {
+		const value = first.getAttribute(attr);
+		if (value && elements.every(e => e.getAttribute(attr) === value)) {
+			steps.push('[' + attr + '=' + cssString(value) + ']');
+		}
+	}
+	const classes = Array.from(first.classList)
+		.filter(c => !looksGenerated(c) && elements.every(e => e.classList.contains(c)))
+		.map(c => '.' + CSS.escape(c));
+	if (classes.length > 1) steps.push(tag + classes.join(''));
+	for (const c of classes) steps.push(tag + c);
+	const role = first.getAttribute('role');
+	if (role && elements.every(e => e.getAttribute('role') === role)) {
+		steps.push(tag + '[role=' + cssString(role) + ']');
+	}
+	// A bare tag only generalizes well when nothing more specific is shared
+	if (tag && (steps.length === 0 || elements.length > 1)) steps.push(tag);
+	return steps;
+}
+
+// containerOf returns the element or shadow root directly containing node
+function containerOf(node) {
+	if (node.parentElement) return node.parentElement;
+	return node.parentNode instanceof ShadowRoot ? node.parentNode : null;
+}
+
+function commonAncestor(elements) {
+	let ancestor = containerOf(elements[0]);
+	while (ancestor && !elements.every(e => ancestor.contains(e))) ancestor = containerOf(ancestor);
+	return ancestor;
+}
+
+// generalizeSelector finds a selector matching all elements and their look-alikes: the
+// most specific shared element-level selector, scoped to the nearest container holding
+// them (for a single element, the nearest container with at least two matches)
+function generalizeSelector(elements) {
+	const steps = similarSteps(elements);
+	let container = elements.length > 1 ? commonAncestor(elements) : containerOf(elements[0]);
+	for (; container; container = containerOf(container)) {
+		for (const step of steps) {
+			let inContainer;
+			try {
+				inContainer = Array.from(container.querySelectorAll(step));
+			} catch (e) {
+				continue;
+			}
+			if (!elements.every(e => inContainer.includes(e))) continue;
+			if (elements.length === 1 && inContainer.length < 2) continue;
+
+			let scoped;
+			if (container instanceof ShadowRoot) {
+				scoped = getCSSSelector(container.host) + ' >>> ' + step;
+			} else if (container === document.body || container === document.documentElement) {
+				scoped = step;
+			} else {
+				scoped = getCSSSelector(container) + ' ' + step;
+			}
+			const matches = cssMatches(scoped);
+			if (elements.every(e => matches.includes(e))) {
+				return { selector: scoped, matches: matches.length };
+			}
+		}
+	}
+
+	// Nothing in common: list the elements individually
+	const selector = elements.map(getCSSSelector).join(', ');
+	return { selector: selector, matches: cssMatches(selector).length };
+}
+`
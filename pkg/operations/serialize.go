@@ -0,0 +1,311 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// richTypeKey marks objects standing in for values JSON can't represent, e.g.
+// {"$brow": "bigint", "value": "9007199254740993"}
+const richTypeKey = "$brow"
+
+// NodeValue is how rich results represent DOM nodes; elements carry a selector and a
+// summary of their outerHTML, text and comment nodes their text
+type NodeValue struct {
+	NodeName string `json:"nodeName"`
+	Selector string `json:"selector,omitempty"`
+	HTML     string `json:"html,omitempty"`
+	Text     string `json:"text,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// serializeScript converts the value it is called on into JSON-safe data, replacing
+// values JSON loses (undefined, NaN, BigInt, Map, Set, nodes, cycles...) with tagged objects
+const serializeScript = `function() {
+%s
+const TYPE = '` + richTypeKey + `';
+const MAX_DEPTH = 32;
+const SUMMARY_LENGTH = 300;
+
+function tagged(type, fields) {
+	return Object.assign({ [TYPE]: type }, fields);
+}
+
+function summarize(s) {
+	return s.length > SUMMARY_LENGTH ? s.slice(0, SUMMARY_LENGTH) + '…' : s;
+}
+
+function isNode(value) {
+	// instanceof fails for nodes of other frames
+	return typeof value.nodeType === 'number' && typeof value.nodeName === 'string';
+}
+
+function serializeNode(node) {
+	const nodeName = node.nodeName.toLowerCase();
+	switch (node.nodeType) {
+	case Node.ELEMENT_NODE: {
+		let selector;
+		try {
+			if (node.isConnected) selector = getCSSSelector(node);
+		} catch (e) {}
+		return tagged('node', { nodeName, selector, html: summarize(node.outerHTML) });
+	}
+	case Node.DOCUMENT_NODE:
+		return tagged('node', { nodeName, url: node.URL });
+	case Node.DOCUMENT_FRAGMENT_NODE:
+		return tagged('node', { nodeName, selector: node.host && node.host.isConnected ? getCSSSelector(node.host) : undefined });
+	default:
+		return tagged('node', { nodeName, text: summarize(node.textContent || '') });
+	}
+}
+
+const ancestors = new Map();
+
+function serialize(value, path, depth) {
+	switch (typeof value) {
+	case 'undefined':
+		return tagged('undefined');
+	case 'bigint':
+		return tagged('bigint', { value: value.toString() });
+	case 'symbol':
+		return tagged('symbol', { description: value.description || '' });
+	case 'function':
+		return tagged('function', { name: value.name });
+	case 'number':
+		if (Object.is(value, -0)) return tagged('number', { value: '-0' });
+		return Number.isFinite(value) ? value : tagged('number', { value: String(value) });
+	case 'string':
+	case 'boolean':
+		return value;
+	}
+	if (value === null) return null;
+
+	if (ancestors.has(value)) return tagged('circular', { path: ancestors.get(value) });
+	if (depth >= MAX_DEPTH) return tagged('truncated', { path });
+
+	ancestors.set(value, path);
+	try {
+		return serializeObject(value, path, depth);
+	} catch (e) {
+		return tagged('error', { name: 'SerializationError', message: String(e && e.message || e) });
+	} finally {
+		ancestors.delete(value);
+	}
+}
+
+function serializeObject(value, path, depth) {
+	const kind = Object.prototype.toString.call(value).slice(8, -1);
+	const child = (v, key) => serialize(v, path + key, depth + 1);
+
+	if (value === value.window) {
+		let url;
+		try { url = value.location.href; } catch (e) {}
+		return tagged('window', { url });
+	}
+	if (isNode(value)) return serializeNode(value);
+
+	switch (kind) {
+	case 'Map': {
+		const entries = [];
+		let i = 0;
+		for (const [k, v] of value) {
+			entries.push([child(k, '[' + i + '].key'), child(v, '[' + i + '].value')]);
+			i++;
+		}
+		return tagged('map', { entries });
+	}
+	case 'Set': {
+		const values = [];
+		let i = 0;
+		for (const v of value) values.push(child(v, '[' + i++ + ']'));
+		return tagged('set', { values });
+	}
+	case 'Date':
+		return tagged('date', { value: isNaN(value) ? 'Invalid Date' : value.toISOString() });
+	case 'RegExp':
+		return tagged('regexp', { value: String(value) });
+	case 'Error':
+		return tagged('error', { name: value.name, message: value.message, stack: value.stack });
+	case 'Promise':
+	case 'WeakMap':
+	case 'WeakSet':
+	case 'WeakRef':
+		return tagged(kind.toLowerCase());
+	case 'ArrayBuffer':
+	case 'SharedArrayBuffer':
+		return tagged('arraybuffer', { byteLength: value.byteLength });
+	}
+
+	if (Array.isArray(value) || (ArrayBuffer.isView(value) && kind !== 'DataView') ||
+			kind === 'NodeList' || kind === 'HTMLCollection' || kind === 'Arguments') {
+		const items = [];
+		for (let i = 0; i < value.length; i++) items.push(child(value[i], '[' + i + ']'));
+		return items;
+	}
+
+	if (typeof value.toJSON === 'function') {
+		return serialize(value.toJSON(), path, depth + 1);
+	}
+
+	const out = {};
+	for (const key of Object.keys(value)) {
+		let v;
+		try {
+			v = value[key];
+		} catch (e) {
+			out[key] = tagged('error', { name: e && e.name, message: String(e && e.message || e) });
+			continue;
+		}
+		out[key] = child(v, /^[A-Za-z_$][\w$]*$/.test(key) ? '.' + key : '[' + JSON.stringify(key) + ']');
+	}
+	return out;
+}
+
+return serialize(this, '$', 0);
+}`
+
+// evaluateRich runs Runtime.evaluate and stores a faithful JSON-safe form of the result in res
+func evaluateRich(script string, res *interface{}, opts ...chromedp.EvaluateOption) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var obj *runtime.RemoteObject
+		if err := chromedp.Evaluate(script, &obj, opts...).Do(ctx); err != nil {
+			return err
+		}
+		if obj.ObjectID == "" {
+			v, err := primitiveValue(obj)
+			if err != nil {
+				return err
+			}
+			*res = v
+			return nil
+		}
+		defer func() { _ = runtime.ReleaseObject(obj.ObjectID).Do(ctx) }()
+
+		serialized, exception, err := runtime.CallFunctionOn(fmt.Sprintf(serializeScript, selectorHelpersJS)).
+			WithObjectID(obj.ObjectID).
+			WithReturnByValue(true).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return json.Unmarshal(serialized.Value, res)
+	})
+}
+
+// primitiveValue converts a remote primitive, tagging undefined, BigInt and non-finite numbers
+func primitiveValue(obj *runtime.RemoteObject) (interface{}, error) {
+	if obj.Type == runtime.TypeUndefined {
+		return map[string]interface{}{richTypeKey: "undefined"}, nil
+	}
+	if u := string(obj.UnserializableValue); u != "" {
+		if digits, ok := strings.CutSuffix(u, "n"); ok {
+			return map[string]interface{}{richTypeKey: "bigint", "value": digits}, nil
+		}
+		return map[string]interface{}{richTypeKey: "number", "value": u}, nil
+	}
+
+	var v interface{}
+	if len(obj.Value) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(obj.Value, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// plainValue turns a rich result into data that decodes naturally into Go types: BigInts
+// become numbers, dates strings, Maps with string keys objects and Sets arrays, while
+// undefined, NaN, Infinity, cycles and functions become null. Nodes and errors stay objects
+// (see NodeValue).
+func plainValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = plainValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		kind, ok := v[richTypeKey].(string)
+		if !ok {
+			out := make(map[string]interface{}, len(v))
+			for key, item := range v {
+				out[key] = plainValue(item)
+			}
+			return out
+		}
+		return plainTagged(kind, v)
+	default:
+		return v
+	}
+}
+
+// plainTagged converts one tagged value for plainValue
+func plainTagged(kind string, v map[string]interface{}) interface{} {
+	switch kind {
+	case "bigint":
+		if digits, ok := v["value"].(string); ok {
+			return json.Number(digits)
+		}
+	case "number":
+		if v["value"] == "-0" {
+			return json.Number("0")
+		}
+	case "date", "regexp":
+		return v["value"]
+	case "set":
+		return plainValue(v["values"])
+	case "map":
+		entries, _ := v["entries"].([]interface{})
+		object := make(map[string]interface{}, len(entries))
+		pairs := make([]interface{}, 0, len(entries))
+		stringKeys := true
+		for _, entry := range entries {
+			pair, ok := entry.([]interface{})
+			if !ok || len(pair) != 2 {
+				continue
+			}
+			key, value := plainValue(pair[0]), plainValue(pair[1])
+			pairs = append(pairs, []interface{}{key, value})
+			if s, ok := key.(string); ok {
+				object[s] = value
+			} else {
+				stringKeys = false
+			}
+		}
+		if stringKeys {
+			return object
+		}
+		return pairs
+	case "node", "error", "window":
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if key != richTypeKey {
+				out[key] = item
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// decodeInto decodes a rich result into dst through plainValue
+func decodeInto(result interface{}, dst interface{}) error {
+	data, err := json.Marshal(plainValue(result))
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
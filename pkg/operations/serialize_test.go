@@ -0,0 +1,97 @@
+package operations
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestPrimitiveValue(t *testing.T) {
+	tests := []struct {
+		obj  *runtime.RemoteObject
+		want interface{}
+	}{
+		{&runtime.RemoteObject{Type: runtime.TypeUndefined}, map[string]interface{}{"$brow": "undefined"}},
+		{&runtime.RemoteObject{Type: runtime.TypeBigint, UnserializableValue: "12345678901234567890n"},
+			map[string]interface{}{"$brow": "bigint", "value": "12345678901234567890"}},
+		{&runtime.RemoteObject{Type: runtime.TypeNumber, UnserializableValue: "NaN"},
+			map[string]interface{}{"$brow": "number", "value": "NaN"}},
+		{&runtime.RemoteObject{Type: runtime.TypeString, Value: []byte(`"hi"`)}, "hi"},
+		{&runtime.RemoteObject{Type: runtime.TypeObject, Subtype: runtime.SubtypeNull, Value: []byte(`null`)}, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := primitiveValue(tt.obj)
+		if err != nil {
+			t.Fatalf("primitiveValue(%+v): %v", tt.obj, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("primitiveValue(%+v) = %#v, want %#v", tt.obj, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	var rich interface{}
+	err := json.Unmarshal([]byte(`{
+		"id": {"$brow": "bigint", "value": "12345678901234567890"},
+		"missing": {"$brow": "undefined"},
+		"ratio": {"$brow": "number", "value": "NaN"},
+		"created": {"$brow": "date", "value": "2024-05-01T10:00:00.000Z"},
+		"tags": {"$brow": "set", "values": ["a", "b"]},
+		"counts": {"$brow": "map", "entries": [["x", 1], ["y", 2]]},
+		"link": {"$brow": "node", "nodeName": "a", "selector": "#home", "html": "<a id=\"home\">Home</a>"},
+		"self": {"$brow": "circular", "path": "$"}
+	}`), &rich)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst struct {
+		ID      *big.Int       `json:"id"`
+		Missing string         `json:"missing"`
+		Ratio   float64        `json:"ratio"`
+		Created time.Time      `json:"created"`
+		Tags    []string       `json:"tags"`
+		Counts  map[string]int `json:"counts"`
+		Link    NodeValue      `json:"link"`
+		Self    interface{}    `json:"self"`
+	}
+	if err := decodeInto(rich, &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.ID.String() != "12345678901234567890" {
+		t.Errorf("ID = %s", dst.ID)
+	}
+	if dst.Missing != "" || dst.Ratio != 0 || dst.Self != nil {
+		t.Errorf("undefined, NaN and cycles should decode to zero values, got %q %v %v", dst.Missing, dst.Ratio, dst.Self)
+	}
+	if !dst.Created.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Created = %v", dst.Created)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"a", "b"}) {
+		t.Errorf("Tags = %v", dst.Tags)
+	}
+	if !reflect.DeepEqual(dst.Counts, map[string]int{"x": 1, "y": 2}) {
+		t.Errorf("Counts = %v", dst.Counts)
+	}
+	if dst.Link != (NodeValue{NodeName: "a", Selector: "#home", HTML: `<a id="home">Home</a>`}) {
+		t.Errorf("Link = %+v", dst.Link)
+	}
+}
+
+func TestPlainValueMapWithObjectKeys(t *testing.T) {
+	rich := map[string]interface{}{
+		"$brow":   "map",
+		"entries": []interface{}{[]interface{}{map[string]interface{}{"x": 1.0}, "v"}},
+	}
+	want := []interface{}{[]interface{}{map[string]interface{}{"x": 1.0}, "v"}}
+	if got := plainValue(rich); !reflect.DeepEqual(got, want) {
+		t.Errorf("plainValue = %#v, want %#v", got, want)
+	}
+}
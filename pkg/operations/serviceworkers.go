@@ -0,0 +1,131 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/serviceworker"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// serviceWorkerQuietPeriod is how long to wait for more registration events after the last one
+	serviceWorkerQuietPeriod = 300 * time.Millisecond
+	// serviceWorkerMaxWait caps the time spent collecting registration events
+	serviceWorkerMaxWait = 2 * time.Second
+)
+
+// ServiceWorker is a service worker registration and its versions
+type ServiceWorker struct {
+	RegistrationID string                 `json:"registrationId"`
+	Scope          string                 `json:"scope"`
+	Versions       []ServiceWorkerVersion `json:"versions,omitempty"`
+}
+
+// ServiceWorkerVersion is one version (script) of a registration
+type ServiceWorkerVersion struct {
+	VersionID     string `json:"versionId"`
+	ScriptURL     string `json:"scriptURL"`
+	Status        string `json:"status"`
+	RunningStatus string `json:"runningStatus"`
+}
+
+// ListServiceWorkers lists the service worker registrations of all origins in the browser profile
+// Chrome reports registrations as events after ServiceWorker.enable, so this waits until
+// the events stop arriving
+func ListServiceWorkers(ctx context.Context) ([]ServiceWorker, error) {
+	var mu sync.Mutex
+	registrations := make(map[serviceworker.RegistrationID]*ServiceWorker)
+	versions := make(map[string]serviceworker.Version)
+	updated := make(chan struct{}, 1)
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		mu.Lock()
+		switch e := ev.(type) {
+		case *serviceworker.EventWorkerRegistrationUpdated:
+			for _, r := range e.Registrations {
+				if r.IsDeleted {
+					delete(registrations, r.RegistrationID)
+					continue
+				}
+				registrations[r.RegistrationID] = &ServiceWorker{
+					RegistrationID: string(r.RegistrationID),
+					Scope:          r.ScopeURL,
+				}
+			}
+		case *serviceworker.EventWorkerVersionUpdated:
+			for _, v := range e.Versions {
+				versions[v.VersionID] = *v
+			}
+		default:
+			mu.Unlock()
+			return
+		}
+		mu.Unlock()
+
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	if err := chromedp.Run(ctx, serviceworker.Enable()); err != nil {
+		return nil, fmt.Errorf("failed to list service workers: %w", err)
+	}
+	defer func() { _ = chromedp.Run(ctx, serviceworker.Disable()) }()
+
+	deadline := time.After(serviceWorkerMaxWait)
+wait:
+	for {
+		select {
+		case <-updated:
+		case <-time.After(serviceWorkerQuietPeriod):
+			break wait
+		case <-deadline:
+			break wait
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, v := range versions {
+		if r, ok := registrations[v.RegistrationID]; ok && v.Status != serviceworker.VersionStatusRedundant {
+			r.Versions = append(r.Versions, ServiceWorkerVersion{
+				VersionID:     v.VersionID,
+				ScriptURL:     v.ScriptURL,
+				Status:        v.Status.String(),
+				RunningStatus: v.RunningStatus.String(),
+			})
+		}
+	}
+
+	result := make([]ServiceWorker, 0, len(registrations))
+	for _, r := range registrations {
+		sort.Slice(r.Versions, func(i, j int) bool { return r.Versions[i].VersionID < r.Versions[j].VersionID })
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Scope < result[j].Scope })
+
+	return result, nil
+}
+
+// UnregisterServiceWorker unregisters the service worker registration with the given scope URL
+func UnregisterServiceWorker(ctx context.Context, scope string) error {
+	if err := chromedp.Run(ctx,
+		serviceworker.Enable(),
+		serviceworker.Unregister(scope),
+		serviceworker.Disable(),
+	); err != nil {
+		return fmt.Errorf("failed to unregister service worker: %w", err)
+	}
+
+	return nil
+}
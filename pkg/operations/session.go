@@ -0,0 +1,76 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// Session is a snapshot of authentication state: cookies for all domains plus web storage per origin
+type Session struct {
+	Cookies []*network.Cookie `json:"cookies"`
+	Origins []OriginStorage   `json:"origins"`
+}
+
+// OriginStorage holds localStorage and sessionStorage items for one origin
+type OriginStorage struct {
+	Origin         string            `json:"origin"`
+	LocalStorage   map[string]string `json:"localStorage,omitempty"`
+	SessionStorage map[string]string `json:"sessionStorage,omitempty"`
+}
+
+// CaptureOriginStorage reads web storage of the page's current origin
+// sessionStorage is per tab, so it is only meaningful in the tab the user worked in
+func CaptureOriginStorage(ctx context.Context, includeSession bool) (*OriginStorage, error) {
+	origin, err := EvaluateIsolated(ctx, "location.origin")
+	if err != nil {
+		return nil, err
+	}
+	originStr, ok := origin.(string)
+	if !ok || originStr == "" || originStr == "null" {
+		return nil, fmt.Errorf("current page has no web origin")
+	}
+
+	result := &OriginStorage{Origin: originStr}
+
+	result.LocalStorage, err = ExportStorage(ctx, LocalStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	if includeSession {
+		result.SessionStorage, err = ExportStorage(ctx, SessionStorage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// RestoreOriginStorage writes the saved items into the storage of storage.Origin, which must be
+// loaded in a frame of the tab (see OpenOrigin); otherwise ErrOriginNotLoaded is returned
+func RestoreOriginStorage(ctx context.Context, storage OriginStorage) error {
+	if len(storage.LocalStorage) > 0 {
+		if err := SetOriginStorageItems(ctx, storage.Origin, LocalStorage, storage.LocalStorage); err != nil {
+			return err
+		}
+	}
+	if len(storage.SessionStorage) > 0 {
+		if err := SetOriginStorageItems(ctx, storage.Origin, SessionStorage, storage.SessionStorage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NormalizeOrigin reduces a URL such as "https://app.example.com/login" to its origin
+func NormalizeOrigin(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid origin %q (expected e.g. https://app.example.com)", rawURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
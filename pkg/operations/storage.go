@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/chromedp"
 )
 
@@ -34,7 +37,7 @@ func GetAllStorage(ctx context.Context, storageType StorageType) (map[string]int
 	`, storageName, storageName, storageName)
 
 	var result interface{}
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, &result)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, &result)); err != nil {
 		return nil, fmt.Errorf("failed to get storage items: %w", err)
 	}
 
@@ -57,7 +60,7 @@ func GetStorageItem(ctx context.Context, storageType StorageType, key string) (i
 	script := fmt.Sprintf("%s.getItem(%s)", string(storageType), string(keyJSON))
 
 	var result interface{}
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, &result)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, &result)); err != nil {
 		return nil, fmt.Errorf("failed to get value: %w", err)
 	}
 
@@ -78,13 +81,35 @@ func SetStorageItem(ctx context.Context, storageType StorageType, key, value str
 
 	script := fmt.Sprintf("%s.setItem(%s, %s)", string(storageType), string(keyJSON), string(valueJSON))
 
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, nil)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, nil)); err != nil {
 		return fmt.Errorf("failed to set value: %w", err)
 	}
 
 	return nil
 }
 
+// SetStorageItems sets several values in storage in a single round-trip
+func SetStorageItems(ctx context.Context, storageType StorageType, items map[string]string) error {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to escape items: %w", err)
+	}
+
+	script := fmt.Sprintf(`
+		((items) => {
+			for (const [key, value] of Object.entries(items)) {
+				%s.setItem(key, value);
+			}
+		})(%s)
+	`, string(storageType), string(itemsJSON))
+
+	if err := chromedp.Run(ctx, evaluateIsolated(script, nil)); err != nil {
+		return fmt.Errorf("failed to set values: %w", err)
+	}
+
+	return nil
+}
+
 // RemoveStorageItem removes an item from storage
 func RemoveStorageItem(ctx context.Context, storageType StorageType, key string) error {
 	// Safely escape the key using JSON encoding
@@ -95,7 +120,7 @@ func RemoveStorageItem(ctx context.Context, storageType StorageType, key string)
 
 	script := fmt.Sprintf("%s.removeItem(%s)", string(storageType), string(keyJSON))
 
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, nil)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, nil)); err != nil {
 		return fmt.Errorf("failed to delete key: %w", err)
 	}
 
@@ -106,9 +131,49 @@ func RemoveStorageItem(ctx context.Context, storageType StorageType, key string)
 func ClearStorage(ctx context.Context, storageType StorageType) error {
 	script := fmt.Sprintf("%s.clear()", string(storageType))
 
-	if err := chromedp.Run(ctx, chromedp.Evaluate(script, nil)); err != nil {
+	if err := chromedp.Run(ctx, evaluateIsolated(script, nil)); err != nil {
 		return fmt.Errorf("failed to clear storage: %w", err)
 	}
 
 	return nil
 }
+
+// OriginDataTypes are the storage types accepted by ClearOriginData
+var OriginDataTypes = []string{
+	"all", "cookies", "local_storage", "indexeddb", "cache_storage", "service_workers",
+	"file_systems", "websql", "shader_cache", "interest_groups", "shared_storage",
+	"storage_buckets", "other",
+}
+
+// ClearOriginData clears the selected types of stored data for an origin with
+// Storage.clearDataForOrigin; no types clears everything
+// An empty origin uses the current page's origin
+func ClearOriginData(ctx context.Context, origin string, types []string) error {
+	if len(types) == 0 {
+		types = []string{"all"}
+	}
+	for _, t := range types {
+		if !slices.Contains(OriginDataTypes, t) {
+			return fmt.Errorf("unknown storage type %q (expected one of %s)", t, strings.Join(OriginDataTypes, ", "))
+		}
+	}
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if origin == "" {
+			var location string
+			if err := evaluateIsolated("location.origin", &location).Do(ctx); err != nil {
+				return err
+			}
+			origin = location
+		}
+		origin, err := NormalizeOrigin(origin)
+		if err != nil {
+			return err
+		}
+		return storage.ClearDataForOrigin(origin, strings.Join(types, ",")).Do(ctx)
+	})); err != nil {
+		return fmt.Errorf("failed to clear data for origin: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DecodeStorageValue parses a stored value as JSON; values that aren't valid JSON are
+// returned as plain strings
+func DecodeStorageValue(s string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return s
+	}
+	return v
+}
+
+// DecodeStorageValues applies DecodeStorageValue to every item
+func DecodeStorageValues(items map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(items))
+	for k, v := range items {
+		result[k] = DecodeStorageValue(v)
+	}
+	return result
+}
+
+// EncodeStorageValue converts a value to the string stored for it: strings are stored as-is,
+// anything else (numbers, booleans, objects, arrays, null) as JSON
+func EncodeStorageValue(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode value: %w", err)
+	}
+	return string(data), nil
+}
+
+// EncodeStorageValues applies EncodeStorageValue to every item
+func EncodeStorageValues(items map[string]interface{}) (map[string]string, error) {
+	result := make(map[string]string, len(items))
+	for k, v := range items {
+		s, err := EncodeStorageValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		result[k] = s
+	}
+	return result, nil
+}
+
+// ExportStorage retrieves all items from storage as strings in one round-trip
+func ExportStorage(ctx context.Context, storageType StorageType) (map[string]string, error) {
+	items, err := GetAllStorage(ctx, storageType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(items))
+	for k, v := range items {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result, nil
+}
+
+// GetStorageJSON reads a JSON-encoded item from storage and unmarshals it into v
+func GetStorageJSON(ctx context.Context, storageType StorageType, key string, v interface{}) error {
+	result, err := GetStorageItem(ctx, storageType, key)
+	if err != nil {
+		return err
+	}
+
+	s, ok := result.(string)
+	if !ok {
+		return fmt.Errorf("key %q not found in %s", key, storageType)
+	}
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		return fmt.Errorf("value of %q is not valid JSON: %w", key, err)
+	}
+	return nil
+}
+
+// SetStorageJSON stores v JSON-encoded, as JSON.stringify would
+func SetStorageJSON(ctx context.Context, storageType StorageType, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	return SetStorageItem(ctx, storageType, key, string(data))
+}
+
+// ImportStorage sets all items in one round-trip; values are converted with EncodeStorageValue
+func ImportStorage(ctx context.Context, storageType StorageType, items map[string]interface{}) error {
+	encoded, err := EncodeStorageValues(items)
+	if err != nil {
+		return err
+	}
+	return SetStorageItems(ctx, storageType, encoded)
+}
@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeStorageValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{`{"theme":"dark"}`, map[string]interface{}{"theme": "dark"}},
+		{`[1,2]`, []interface{}{json.Number("1"), json.Number("2")}},
+		{`12345678901234567890`, json.Number("12345678901234567890")},
+		{`true`, true},
+		{`"quoted"`, "quoted"},
+		{`dark`, "dark"},
+		{`{"a":1} trailing`, `{"a":1} trailing`},
+		{``, ""},
+	}
+
+	for _, tt := range tests {
+		if got := DecodeStorageValue(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DecodeStorageValue(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeStorageValues(t *testing.T) {
+	items := map[string]interface{}{
+		"theme":    "dark",
+		"count":    json.Number("3"),
+		"enabled":  false,
+		"settings": map[string]interface{}{"lang": "en"},
+		"empty":    nil,
+	}
+
+	got, err := EncodeStorageValues(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"theme":    "dark",
+		"count":    "3",
+		"enabled":  "false",
+		"settings": `{"lang":"en"}`,
+		"empty":    "null",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeStorageValues() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// fileChooserWait is how long SetInputFiles waits for a clicked element to open a file chooser
+const fileChooserWait = 5 * time.Second
+
+// SetInputFiles selects files in the file input matching the selector, firing its input and
+// change events as if the user had picked them. The input may be hidden. If the selector
+// matches another element, such as a button or label that opens a file chooser, the element
+// is clicked and the files are given to the chooser instead of showing it.
+// Selectors may step into iframes and shadow roots with " >>> ".
+func SetInputFiles(ctx context.Context, selector string, files []string) error {
+	if err := setInputFiles(ctx, "", selector, files); err != nil {
+		return fmt.Errorf("failed to upload to %s: %w", selector, err)
+	}
+	return nil
+}
+
+// SetInputFilesInFrame selects files like SetInputFiles for an element inside a frame chosen
+// by name, URL substring or iframe selector (see ListFrames)
+func SetInputFilesInFrame(ctx context.Context, frame, selector string, files []string) error {
+	if err := setInputFiles(ctx, frame, selector, files); err != nil {
+		return fmt.Errorf("failed to upload to %s in frame %s: %w", selector, frame, err)
+	}
+	return nil
+}
+
+// setInputFiles resolves the element and sets files directly or through the file chooser
+func setInputFiles(ctx context.Context, frameSpec, selector string, files []string) error {
+	paths, err := uploadPaths(files)
+	if err != nil {
+		return err
+	}
+
+	start, err := resolveFrame(ctx, frameSpec)
+	if err != nil {
+		return err
+	}
+	frame, element, err := start.waitSelector(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		IsFile   bool `json:"isFile"`
+		Multiple bool `json:"multiple"`
+	}
+	err = frame.run(chromedp.ActionFunc(func(ctx context.Context) error {
+		res, exception, err := runtime.CallFunctionOn(`function() {
+			return { isFile: this.localName === 'input' && this.type === 'file', multiple: !!this.multiple };
+		}`).WithObjectID(element).WithReturnByValue(true).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exception != nil {
+			return exception
+		}
+		return json.Unmarshal(res.Value, &input)
+	}))
+	if err != nil {
+		return err
+	}
+
+	if !input.IsFile {
+		return chooseFiles(ctx, frame, element, paths)
+	}
+	if len(paths) > 1 && !input.Multiple {
+		return errors.New("the file input accepts a single file")
+	}
+	return frame.run(dom.SetFileInputFiles(paths).WithObjectID(element))
+}
+
+// chooseFiles clicks an element that opens a file chooser and answers the chooser with paths
+func chooseFiles(ctx context.Context, frame *frameHandle, element runtime.RemoteObjectID, paths []string) error {
+	opened := make(chan *page.EventFileChooserOpened, 1)
+	listenCtx, cancel := context.WithCancel(frame.ctx)
+	defer cancel()
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		if e, ok := ev.(*page.EventFileChooserOpened); ok {
+			select {
+			case opened <- e:
+			default:
+			}
+		}
+	})
+
+	if err := frame.run(page.SetInterceptFileChooserDialog(true)); err != nil {
+		return fmt.Errorf("failed to intercept the file chooser: %w", err)
+	}
+	defer func() { _ = frame.run(page.SetInterceptFileChooserDialog(false)) }()
+
+	// A real click, since pages may only open file choosers in response to user input
+	x, y, err := frame.elementCenter(element)
+	if err != nil {
+		return err
+	}
+	if err := chromedp.Run(ctx, chromedp.MouseClickXY(x, y)); err != nil {
+		return err
+	}
+
+	var chooser *page.EventFileChooserOpened
+	select {
+	case chooser = <-opened:
+	case <-time.After(fileChooserWait):
+		return errors.New("the element is not a file input and clicking it opened no file chooser")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if chooser.BackendNodeID == 0 {
+		return errors.New("the file chooser doesn't belong to a file input")
+	}
+	if len(paths) > 1 && chooser.Mode == page.FileChooserOpenedModeSelectSingle {
+		return errors.New("the file chooser accepts a single file")
+	}
+	return frame.run(dom.SetFileInputFiles(paths).WithBackendNodeID(chooser.BackendNodeID))
+}
+
+// uploadPaths returns absolute paths of files, which must exist and not be directories
+func uploadPaths(files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files given")
+	}
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", file, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory", file)
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
@@ -0,0 +1,30 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUploadPaths(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(file, []byte("%PDF"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(dir)
+	paths, err := uploadPaths([]string{"report.pdf"})
+	if err != nil {
+		t.Fatalf("uploadPaths: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != file {
+		t.Errorf("uploadPaths = %q, want [%q]", paths, file)
+	}
+
+	for _, files := range [][]string{nil, {"missing.txt"}, {dir}} {
+		if _, err := uploadPaths(files); err == nil {
+			t.Errorf("uploadPaths(%q) succeeded, want an error", files)
+		}
+	}
+}